@@ -0,0 +1,84 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// AuthCode is the EPP auth code used to transfer a domain between
+// registrars. It hides its value when printed with fmt, logged with slog or
+// marshalled to JSON, so it doesn't leak into logs. Use Reveal to get the
+// actual code.
+type AuthCode struct {
+	value string
+}
+
+// NewAuthCode wraps an auth code obtained elsewhere, like from another
+// registrar
+func NewAuthCode(code string) AuthCode {
+	return AuthCode{value: code}
+}
+
+// Reveal returns the actual auth code
+func (a AuthCode) Reveal() string {
+	return a.value
+}
+
+// IsZero reports whether the auth code is empty
+func (a AuthCode) IsZero() bool {
+	return a.value == ""
+}
+
+// String returns a placeholder instead of the auth code
+func (a AuthCode) String() string {
+	return redacted
+}
+
+// Format prints a placeholder instead of the auth code, whatever the verb
+func (a AuthCode) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, redacted)
+}
+
+// LogValue logs a placeholder instead of the auth code
+func (a AuthCode) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// MarshalJSON marshals a placeholder instead of the auth code
+func (a AuthCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
+
+// GetAuthCode returns the auth code of a domain, needed to transfer it out
+// to another registrar
+func (c *Client) GetAuthCode(
+	ctx context.Context, domain string,
+) (AuthCode, error) {
+	params := map[string]interface{}{
+		"domain": domain,
+	}
+
+	data, err := c.Request(ctx, "get-auth-code", params)
+	if err != nil {
+		return AuthCode{}, err
+	}
+
+	type Response struct {
+		AuthCode string `json:"auth_code"`
+	}
+
+	var response Response
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return AuthCode{}, err
+	}
+
+	return NewAuthCode(response.AuthCode), nil
+}
+
+// GetAuthCode is a wrapper around Client.GetAuthCode for the given token.
+func GetAuthCode(token string, domain string) (AuthCode, error) {
+	return newDefaultClient(token).GetAuthCode(context.Background(), domain)
+}
@@ -0,0 +1,88 @@
+package gonjalla
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestGetAuthCodeExpected(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"auth_code": "s3cr3t-c0de"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	code, err := GetAuthCode(token, domain)
+	if err != nil {
+		t.Error(err)
+	}
+
+	assert.Equal(t, "s3cr3t-c0de", code.Reveal())
+	assert.False(t, code.IsZero())
+}
+
+func TestGetAuthCodeError(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 0,
+			"message": "Testing error"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	code, err := GetAuthCode(token, domain)
+	assert.Error(t, err)
+	assert.True(t, code.IsZero())
+}
+
+func TestAuthCodeRedacted(t *testing.T) {
+	code := NewAuthCode("s3cr3t-c0de")
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		assert.NotContains(t, fmt.Sprintf(verb, code), "s3cr3t", verb)
+	}
+	assert.Equal(t, "[REDACTED]", code.String())
+	assert.NotContains(t, fmt.Sprint(struct{ Code AuthCode }{code}), "s3cr3t")
+
+	data, err := json.Marshal(map[string]AuthCode{"code": code})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+
+	var output bytes.Buffer
+	slog.New(slog.NewJSONHandler(&output, nil)).Info("code", "code", code)
+	assert.NotContains(t, output.String(), "s3cr3t")
+}
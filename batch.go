@@ -0,0 +1,180 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// BatchMethod is the Call.Method of batches, as seen by middlewares and
+// rate limiters
+const BatchMethod string = "batch"
+
+// BatchCall is one of the calls sent in a batch by Client.Batch
+type BatchCall struct {
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// BatchResult is the outcome of one of the calls of a batch
+type BatchResult struct {
+	// ID is the JSON-RPC id the call was sent with
+	ID int
+	// Method is the API method of the call
+	Method string
+	// Result is the raw `result` of the call. Only set if Err is nil.
+	Result json.RawMessage
+	// Err is the error of this call, usually an *APIError
+	Err error
+}
+
+// batchRequest is a single request inside a JSON-RPC batch. Unlike single
+// requests, these need an `id` so responses can be matched to them.
+type batchRequest struct {
+	JSONRPC string                 `json:"jsonrpc"`
+	ID      int                    `json:"id"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params"`
+}
+
+// batchResponse is a single response inside a JSON-RPC batch
+type batchResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// batchRejectedError is returned when the API doesn't answer a batch with
+// an array of responses, meaning it doesn't support batches
+type batchRejectedError struct {
+	err error
+}
+
+func (e *batchRejectedError) Error() string {
+	return fmt.Sprintf("batch rejected: %s", e.err)
+}
+
+func (e *batchRejectedError) Unwrap() error {
+	return e.err
+}
+
+// Batch sends several calls to the API in a single HTTP request, as a
+// JSON-RPC batch. Results are returned in the same order as the calls, each
+// with its own result or error.
+//
+// If the API rejects the batch, the calls are sent one by one instead. The
+// returned error is only set if the batch couldn't be sent at all, like on
+// network errors; API errors of each call are in its BatchResult.
+func (c *Client) Batch(
+	ctx context.Context, calls []BatchCall,
+) ([]BatchResult, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	call := c.newCall(BatchMethod, nil)
+	call.Batch = calls
+
+	data, err := c.do(ctx, call)
+
+	var rejected *batchRejectedError
+	if errors.As(err, &rejected) {
+		return c.batchFallback(ctx, calls), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []batchResponse
+	err = json.Unmarshal(data, &responses)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]batchResponse, len(responses))
+	for _, response := range responses {
+		byID[response.ID] = response
+	}
+
+	results := make([]BatchResult, len(calls))
+	for i, batchCall := range calls {
+		result := BatchResult{ID: i + 1, Method: batchCall.Method}
+
+		response, ok := byID[result.ID]
+		switch {
+		case !ok:
+			result.Err = fmt.Errorf(
+				"%s: missing response for id %d", batchCall.Method, result.ID,
+			)
+		case response.Error != nil && string(response.Error) != "null":
+			result.Err = newAPIError(batchCall.Method, response.Error)
+		case response.Result == nil:
+			result.Err = fmt.Errorf("Missing result for id %d", result.ID)
+		default:
+			result.Result = response.Result
+		}
+
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+// batchFallback sends each call of a batch on its own
+func (c *Client) batchFallback(
+	ctx context.Context, calls []BatchCall,
+) []BatchResult {
+	results := make([]BatchResult, len(calls))
+	for i, batchCall := range calls {
+		data, err := c.Request(ctx, batchCall.Method, batchCall.Params)
+		results[i] = BatchResult{
+			ID:     i + 1,
+			Method: batchCall.Method,
+			Result: data,
+			Err:    err,
+		}
+	}
+
+	return results
+}
+
+// decodeBatch checks the response to a batch, returning the raw array of
+// responses. A response that isn't an array is taken as the API rejecting
+// batches, unless it's an authentication, rate limiting or server error.
+func decodeBatch(resp *http.Response, body []byte) (json.RawMessage, error) {
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if !success {
+			return nil, newHTTPError(BatchMethod, resp, body, nil)
+		}
+		return trimmed, nil
+	}
+
+	var data response
+	err := json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, newHTTPError(BatchMethod, resp, body, err)
+	}
+
+	var cause error = fmt.Errorf("unexpected response %s", body)
+	if data.Error != nil && string(data.Error) != "null" {
+		cause = newAPIError(BatchMethod, data.Error)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden,
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return nil, newHTTPError(BatchMethod, resp, body, cause)
+	case !success:
+		cause = newHTTPError(BatchMethod, resp, body, cause)
+	}
+
+	return nil, &batchRejectedError{err: cause}
+}
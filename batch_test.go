@@ -0,0 +1,120 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestBatchExpected(t *testing.T) {
+	testData := `[
+		{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"error": {"code": 404, "message": "Domain not found"}
+		},
+		{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"result": {"records": []}
+		}
+	]`
+
+	var sent []map[string]interface{}
+	client, err := NewClient("test-token", WithHTTPClient(&mocks.MockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			assert.Nil(t, json.Unmarshal(body, &sent))
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(
+					bytes.NewReader([]byte(testData)),
+				),
+			}, nil
+		},
+	}))
+	assert.Nil(t, err)
+
+	results, err := client.Batch(context.Background(), []BatchCall{
+		{
+			Method: "list-records",
+			Params: map[string]interface{}{"domain": "testing1.com"},
+		},
+		{
+			Method: "list-records",
+			Params: map[string]interface{}{"domain": "testing2.com"},
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Len(t, sent, 2)
+	assert.Equal(t, "2.0", sent[0]["jsonrpc"])
+	assert.Equal(t, float64(1), sent[0]["id"])
+	assert.Equal(t, float64(2), sent[1]["id"])
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, 1, results[0].ID)
+	assert.Nil(t, results[0].Err)
+	assert.JSONEq(t, `{"records": []}`, string(results[0].Result))
+	assert.Equal(t, 2, results[1].ID)
+	assert.Equal(t, "list-records", results[1].Method)
+	assert.True(t, IsNotFound(results[1].Err))
+}
+
+func TestBatchFallback(t *testing.T) {
+	responses := []string{
+		`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid"}}`,
+		`{"jsonrpc": "2.0", "result": {"domains": []}}`,
+		`{"jsonrpc": "2.0", "error": {"code": 0, "message": "Testing error"}}`,
+	}
+
+	var methods []string
+	client, err := NewClient("test-token", WithHTTPClient(&mocks.MockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			var single request
+			if json.Unmarshal(body, &single) == nil {
+				methods = append(methods, single.Method)
+			}
+
+			testData := responses[0]
+			responses = responses[1:]
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(
+					bytes.NewReader([]byte(testData)),
+				),
+			}, nil
+		},
+	}))
+	assert.Nil(t, err)
+
+	results, err := client.Batch(context.Background(), []BatchCall{
+		{Method: "list-domains"},
+		{Method: "list-servers"},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"list-domains", "list-servers"}, methods)
+	assert.Len(t, results, 2)
+	assert.Nil(t, results[0].Err)
+	assert.JSONEq(t, `{"domains": []}`, string(results[0].Result))
+	assert.Error(t, results[1].Err)
+}
+
+func TestBatchServerError(t *testing.T) {
+	client := newStatusClient(t, 502, "Bad Gateway")
+
+	results, err := client.Batch(context.Background(), []BatchCall{
+		{Method: "list-domains"},
+	})
+	assert.Nil(t, results)
+	assert.True(t, IsRetryable(err))
+}
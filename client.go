@@ -0,0 +1,144 @@
+package gonjalla
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client talks to Njalla's API on behalf of a single account.
+// Every API call in this library is available as a method on Client. Create
+// one with NewClient and reuse it; a Client holds no per-request state.
+type Client struct {
+	token          string
+	httpClient     HTTPClient
+	baseURL        string
+	userAgent      string
+	headers        http.Header
+	retryPolicy    *RetryPolicy
+	limiter        Limiter
+	methodLimiters map[string]Limiter
+	middlewares    []Middleware
+}
+
+// Option configures a Client when passed to NewClient.
+type Option func(*Client) error
+
+// NewClient returns a Client authenticating with the given API token.
+// Without options it sends its requests through DefaultHTTPClient.
+func NewClient(token string, opts ...Option) (*Client, error) {
+	if token == "" {
+		return nil, errors.New("token must not be empty")
+	}
+
+	client := newDefaultClient(token)
+	for _, opt := range opts {
+		err := opt(client)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// newDefaultClient returns a Client with no options applied. Used by the
+// package-level functions, which predate Client.
+func newDefaultClient(token string) *Client {
+	return &Client{
+		token:      token,
+		httpClient: DefaultHTTPClient,
+		baseURL:    endpoint,
+		userAgent:  userAgent,
+		headers:    http.Header{},
+	}
+}
+
+// WithHTTPClient sets the HTTPClient used to send requests.
+// Useful to configure timeouts or proxies, or to inject mocks in tests.
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *Client) error {
+		if httpClient == nil {
+			return errors.New("HTTP client must not be nil")
+		}
+		c.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithBaseURL sets the URL requests are sent to, instead of Njalla's API
+// endpoint. Useful to go through a proxy, or to test against a local server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) error {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return fmt.Errorf("base URL %q must be http or https", baseURL)
+		}
+		if parsed.Host == "" {
+			return fmt.Errorf("base URL %q has no host", baseURL)
+		}
+		c.baseURL = parsed.String()
+		return nil
+	}
+}
+
+// WithUserAgent prepends the given product to the User-Agent sent with every
+// request, like `my-tool/1.0 gonjalla (+https://...)`.
+func WithUserAgent(prefix string) Option {
+	return func(c *Client) error {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			return errors.New("user agent prefix must not be empty")
+		}
+		if strings.ContainsAny(prefix, "\r\n") {
+			return errors.New("user agent prefix must not contain newlines")
+		}
+		c.userAgent = fmt.Sprintf("%s %s", prefix, userAgent)
+		return nil
+	}
+}
+
+// WithHeader adds a static header sent with every request. The
+// `Authorization`, `Content-Type` and `User-Agent` headers are managed by the
+// Client and can't be set this way.
+func WithHeader(key string, value string) Option {
+	return func(c *Client) error {
+		if !validHeaderName(key) {
+			return fmt.Errorf("invalid header name %q", key)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %q must not contain newlines", key)
+		}
+
+		key = http.CanonicalHeaderKey(key)
+		switch key {
+		case "Authorization", "Content-Type", "User-Agent":
+			return fmt.Errorf("header %q is managed by the client", key)
+		}
+
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name, as
+// defined by the `token` rule of RFC 7230.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	const separators = "\"(),/:;<=>?@[\\]{}"
+	for _, r := range name {
+		if r <= 0x20 || r > 0x7e || strings.ContainsRune(separators, r) {
+			return false
+		}
+	}
+
+	return true
+}
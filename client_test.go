@@ -0,0 +1,206 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+// newMockClient returns a Client answering each request with the status and
+// body given by respond, which is passed the decoded request and how many
+// requests were sent before it. Batches are decoded as a single request with
+// BatchMethod as its method. Also returns a pointer to the requests sent.
+func newMockClient(
+	t *testing.T, respond func(sent request, n int) (int, string), opts ...Option,
+) (*Client, *[]request) {
+	var mu sync.Mutex
+	var sent []request
+
+	opts = append(opts, WithHTTPClient(&mocks.MockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(req.Body)
+			single := request{Method: BatchMethod}
+			if !bytes.HasPrefix(body, []byte("[")) {
+				assert.Nil(t, json.Unmarshal(body, &single))
+			}
+
+			mu.Lock()
+			n := len(sent)
+			sent = append(sent, single)
+			mu.Unlock()
+
+			status, testData := respond(single, n)
+			contentType := "application/json"
+			if !json.Valid([]byte(testData)) {
+				contentType = "text/html"
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Content-Type": {contentType}},
+				Body: ioutil.NopCloser(
+					bytes.NewReader([]byte(testData)),
+				),
+			}, nil
+		},
+	}))
+
+	client, err := NewClient("test-token", opts...)
+	assert.Nil(t, err)
+
+	return client, &sent
+}
+
+// newResponsesClient returns a Client answering each request with the next
+// of the given bodies, repeating the last one, and a pointer to the requests
+// that were sent.
+func newResponsesClient(t *testing.T, bodies ...string) (*Client, *[]request) {
+	return newMockClient(t, func(_ request, n int) (int, string) {
+		if n >= len(bodies) {
+			n = len(bodies) - 1
+		}
+		return 200, bodies[n]
+	})
+}
+
+// methodsOf returns the methods of the given requests
+func methodsOf(sent []request) []string {
+	methods := make([]string, len(sent))
+	for i, single := range sent {
+		methods[i] = single.Method
+	}
+	return methods
+}
+
+func TestNewClientEmptyToken(t *testing.T) {
+	client, err := NewClient("")
+	assert.Nil(t, client)
+	assert.Error(t, err)
+}
+
+func TestNewClientNilHTTPClient(t *testing.T) {
+	client, err := NewClient("test-token", WithHTTPClient(nil))
+	assert.Nil(t, client)
+	assert.Error(t, err)
+}
+
+func TestClientsAreIndependent(t *testing.T) {
+	newMock := func(authorizations *[]string) *mocks.MockClient {
+		return &mocks.MockClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				*authorizations = append(
+					*authorizations, req.Header.Get("Authorization"),
+				)
+				testData := `{"jsonrpc": "2.0", "result": {"domains": []}}`
+				return &http.Response{
+					StatusCode: 200,
+					Body: ioutil.NopCloser(
+						bytes.NewReader([]byte(testData)),
+					),
+				}, nil
+			},
+		}
+	}
+
+	var first, second []string
+	client1, err := NewClient("token-1", WithHTTPClient(newMock(&first)))
+	assert.Nil(t, err)
+	client2, err := NewClient("token-2", WithHTTPClient(newMock(&second)))
+	assert.Nil(t, err)
+
+	_, err = client1.ListDomains(context.Background())
+	assert.Nil(t, err)
+	_, err = client2.ListDomains(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{"Njalla token-1"}, first)
+	assert.Equal(t, []string{"Njalla token-2"}, second)
+}
+
+func TestRequestPassesContext(t *testing.T) {
+	type key struct{}
+	ctx, cancel := context.WithCancel(
+		context.WithValue(context.Background(), key{}, "value"),
+	)
+	cancel()
+
+	client, err := NewClient("test-token", WithHTTPClient(&mocks.MockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "value", req.Context().Value(key{}))
+			return nil, req.Context().Err()
+		},
+	}))
+	assert.Nil(t, err)
+
+	_, err = client.ListRecords(ctx, "testing.com")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClientEndpointAndHeaders(t *testing.T) {
+	var sent *http.Request
+	client, err := NewClient(
+		"test-token",
+		WithBaseURL("http://localhost:8080/api/1/"),
+		WithUserAgent("my-tool/1.0"),
+		WithHeader("X-Request-Source", "reconciler"),
+		WithHTTPClient(&mocks.MockClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				sent = req
+				testData := `{"jsonrpc": "2.0", "result": {"domains": []}}`
+				return &http.Response{
+					StatusCode: 200,
+					Body: ioutil.NopCloser(
+						bytes.NewReader([]byte(testData)),
+					),
+				}, nil
+			},
+		}),
+	)
+	assert.Nil(t, err)
+
+	_, err = client.ListDomains(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, "http://localhost:8080/api/1/", sent.URL.String())
+	assert.Equal(
+		t, "my-tool/1.0 "+userAgent, sent.Header.Get("User-Agent"),
+	)
+	assert.Equal(t, "reconciler", sent.Header.Get("X-Request-Source"))
+	assert.Equal(t, "application/json", sent.Header.Get("Content-Type"))
+	assert.Equal(t, "Njalla test-token", sent.Header.Get("Authorization"))
+}
+
+func TestClientDefaultEndpoint(t *testing.T) {
+	client, err := NewClient("test-token")
+	assert.Nil(t, err)
+	assert.Equal(t, endpoint, client.baseURL)
+	assert.Equal(t, userAgent, client.userAgent)
+}
+
+func TestClientOptionValidation(t *testing.T) {
+	invalid := []Option{
+		WithBaseURL("njal.la/api/1/"),
+		WithBaseURL("ftp://njal.la/api/1/"),
+		WithBaseURL("https:///api/1/"),
+		WithUserAgent(""),
+		WithUserAgent("tool\r\nX-Injected: 1"),
+		WithHeader("", "value"),
+		WithHeader("X Space", "value"),
+		WithHeader("X-Header", "line\nbreak"),
+		WithHeader("authorization", "Njalla other-token"),
+		WithHeader("User-Agent", "tool"),
+	}
+
+	for _, option := range invalid {
+		client, err := NewClient("test-token", option)
+		assert.Nil(t, client)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,356 @@
+package gonjalla
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// DNSSECAlgorithms maps the DNSSEC algorithm numbers that can be used to
+// sign zones to their mnemonic, as per the IANA "DNS Security Algorithm
+// Numbers" registry
+var DNSSECAlgorithms = map[uint8]string{
+	1:  "RSAMD5",
+	3:  "DSA",
+	5:  "RSASHA1",
+	6:  "DSA-NSEC3-SHA1",
+	7:  "RSASHA1-NSEC3-SHA1",
+	8:  "RSASHA256",
+	10: "RSASHA512",
+	12: "ECC-GOST",
+	13: "ECDSAP256SHA256",
+	14: "ECDSAP384SHA384",
+	15: "ED25519",
+	16: "ED448",
+	17: "SM2SM3",
+	23: "ECC-GOST12",
+}
+
+// DSDigestTypes maps the DS record digest type numbers to their mnemonic,
+// as per the IANA "Delegation Signer (DS) Resource Record (RR) Type Digest
+// Algorithms" registry
+var DSDigestTypes = map[uint8]string{
+	1: "SHA-1",
+	2: "SHA-256",
+	3: "GOST R 34.11-94",
+	4: "SHA-384",
+	5: "GOST R 34.11-2012",
+	6: "SM3",
+}
+
+// Length in bytes of the digests of each DS digest type
+var dsDigestLengths = map[uint8]int{
+	1: sha1.Size,
+	2: sha256.Size,
+	3: 32,
+	4: sha512.Size384,
+	5: 32,
+	6: 32,
+}
+
+// Hash functions of the DS digest types DSFromDNSKEY can compute
+var dsDigestHashes = map[uint8]func() hash.Hash{
+	1: sha1.New,
+	2: sha256.New,
+	4: sha512.New384,
+}
+
+// DSRecord contains data returned by `list-dnssec`. A DS record is published
+// in the parent zone, and points to the key signing key of the domain.
+type DSRecord struct {
+	ID         string `json:"id,omitempty"`
+	KeyTag     uint16 `json:"key_tag"`
+	Algorithm  uint8  `json:"algorithm"`
+	DigestType uint8  `json:"digest_type"`
+	Digest     string `json:"digest"`
+}
+
+// Validate checks the algorithm and digest type against the IANA
+// registries, and that the digest is hex of the right length for its type
+func (d DSRecord) Validate() error {
+	if _, ok := DNSSECAlgorithms[d.Algorithm]; !ok {
+		return fmt.Errorf("unknown DNSSEC algorithm %d", d.Algorithm)
+	}
+
+	length, ok := dsDigestLengths[d.DigestType]
+	if !ok {
+		return fmt.Errorf("unknown DS digest type %d", d.DigestType)
+	}
+
+	digest, err := hex.DecodeString(d.Digest)
+	if err != nil {
+		return fmt.Errorf("DS digest is not hex: %w", err)
+	}
+	if len(digest) != length {
+		return fmt.Errorf(
+			"%s DS digest must be %d bytes long, got %d",
+			DSDigestTypes[d.DigestType], length, len(digest),
+		)
+	}
+
+	return nil
+}
+
+// DSFromDNSKEY computes the DS record for a DNSKEY record given in zone file
+// presentation format, like:
+//
+//	example.com. 3600 IN DNSKEY 257 3 13 mdsswUyr3DPW132mOi8V9xESWE8jTo0d...
+//
+// The owner name is part of the digest, so it must be given explicitly.
+// Digest types 1 (SHA-1), 2 (SHA-256) and 4 (SHA-384) are supported.
+func DSFromDNSKEY(dnskey string, digestType uint8) (DSRecord, error) {
+	newHash, ok := dsDigestHashes[digestType]
+	if !ok {
+		return DSRecord{}, fmt.Errorf(
+			"can't compute DS digest type %d", digestType,
+		)
+	}
+
+	owner, rdata, err := parseDNSKEY(dnskey)
+	if err != nil {
+		return DSRecord{}, err
+	}
+
+	h := newHash()
+	h.Write(owner)
+	h.Write(rdata)
+
+	return DSRecord{
+		KeyTag:     dnskeyTag(rdata),
+		Algorithm:  rdata[3],
+		DigestType: digestType,
+		Digest:     strings.ToUpper(hex.EncodeToString(h.Sum(nil))),
+	}, nil
+}
+
+// parseDNSKEY parses a DNSKEY record in presentation format, returning its
+// owner name in canonical wire format and its RDATA
+func parseDNSKEY(dnskey string) ([]byte, []byte, error) {
+	var fields []string
+	for _, line := range strings.Split(dnskey, "\n") {
+		if i := strings.IndexByte(line, ';'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.NewReplacer("(", " ", ")", " ").Replace(line)
+		fields = append(fields, strings.Fields(line)...)
+	}
+
+	typeIndex := -1
+	for i, field := range fields {
+		if strings.EqualFold(field, "DNSKEY") {
+			typeIndex = i
+			break
+		}
+	}
+	if typeIndex < 1 || !isOwnerField(fields[0]) {
+		return nil, nil, errors.New("DNSKEY record must start with its owner")
+	}
+	if len(fields) < typeIndex+5 {
+		return nil, nil, errors.New("DNSKEY record is missing fields")
+	}
+
+	owner, err := canonicalName(fields[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rdataFields := fields[typeIndex+1:]
+	flags, err := strconv.ParseUint(rdataFields[0], 10, 16)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid DNSKEY flags %q", rdataFields[0])
+	}
+	if flags&0x0100 == 0 {
+		return nil, nil, errors.New("DNSKEY is not a zone key")
+	}
+
+	protocol, err := strconv.ParseUint(rdataFields[1], 10, 8)
+	if err != nil || protocol != 3 {
+		return nil, nil, fmt.Errorf(
+			"invalid DNSKEY protocol %q, must be 3", rdataFields[1],
+		)
+	}
+
+	algorithm, err := strconv.ParseUint(rdataFields[2], 10, 8)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"invalid DNSKEY algorithm %q", rdataFields[2],
+		)
+	}
+	if _, ok := DNSSECAlgorithms[uint8(algorithm)]; !ok {
+		return nil, nil, fmt.Errorf("unknown DNSSEC algorithm %d", algorithm)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(
+		strings.Join(rdataFields[3:], ""),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("DNSKEY public key is not base64: %w", err)
+	}
+	if len(key) < 3 {
+		return nil, nil, errors.New("DNSKEY public key is too short")
+	}
+
+	rdata := make([]byte, 4, 4+len(key))
+	binary.BigEndian.PutUint16(rdata, uint16(flags))
+	rdata[2] = uint8(protocol)
+	rdata[3] = uint8(algorithm)
+	rdata = append(rdata, key...)
+
+	return owner, rdata, nil
+}
+
+// isOwnerField reports whether the first field of a record can be its owner
+// name, rather than its TTL or class with the owner left out
+func isOwnerField(field string) bool {
+	switch strings.ToUpper(field) {
+	case "IN", "CH", "HS":
+		return false
+	}
+
+	_, err := strconv.ParseUint(field, 10, 32)
+	return err != nil
+}
+
+// canonicalName returns a domain name in canonical DNS wire format, as per
+// RFC 4034 section 6.2: lowercase, and as length-prefixed labels
+func canonicalName(name string) ([]byte, error) {
+	if strings.ContainsRune(name, '\\') {
+		return nil, fmt.Errorf("escaped owner names are not supported: %q", name)
+	}
+
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" {
+		return []byte{0}, nil
+	}
+
+	var wire []byte
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("invalid owner name %q", name)
+		}
+		wire = append(wire, byte(len(label)))
+		wire = append(wire, label...)
+	}
+	if len(wire)+1 > 255 {
+		return nil, fmt.Errorf("owner name %q is too long", name)
+	}
+
+	return append(wire, 0), nil
+}
+
+// dnskeyTag computes the key tag of a DNSKEY given its RDATA, as per
+// RFC 4034 appendix B
+func dnskeyTag(rdata []byte) uint16 {
+	if rdata[3] == 1 {
+		// RSAMD5 keys use the last bytes of the modulus instead
+		return binary.BigEndian.Uint16(rdata[len(rdata)-3:])
+	}
+
+	var sum uint32
+	for i, b := range rdata {
+		if i&1 == 0 {
+			sum += uint32(b) << 8
+		} else {
+			sum += uint32(b)
+		}
+	}
+	sum += sum >> 16 & 0xffff
+
+	return uint16(sum)
+}
+
+// ListDNSSEC returns a listing of the DS records of a given domain
+func (c *Client) ListDNSSEC(
+	ctx context.Context, domain string,
+) ([]DSRecord, error) {
+	params := map[string]interface{}{
+		"domain": domain,
+	}
+
+	data, err := c.Request(ctx, "list-dnssec", params)
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		DNSSEC []DSRecord `json:"dnssec"`
+	}
+
+	var response Response
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.DNSSEC, nil
+}
+
+// ListDNSSEC is a wrapper around Client.ListDNSSEC for the given token.
+func ListDNSSEC(token string, domain string) ([]DSRecord, error) {
+	return newDefaultClient(token).ListDNSSEC(context.Background(), domain)
+}
+
+// AddDNSSEC adds a DS record to a given domain. Returns the DS record as
+// returned by the API, with its ID filled in.
+func (c *Client) AddDNSSEC(
+	ctx context.Context, domain string, ds DSRecord,
+) (DSRecord, error) {
+	err := ds.Validate()
+	if err != nil {
+		return DSRecord{}, err
+	}
+
+	params := map[string]interface{}{
+		"domain":      domain,
+		"key_tag":     ds.KeyTag,
+		"algorithm":   ds.Algorithm,
+		"digest_type": ds.DigestType,
+		"digest":      ds.Digest,
+	}
+
+	data, err := c.Request(ctx, "add-dnssec", params)
+	if err != nil {
+		return DSRecord{}, err
+	}
+
+	var response DSRecord
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return DSRecord{}, err
+	}
+
+	return response, nil
+}
+
+// AddDNSSEC is a wrapper around Client.AddDNSSEC for the given token.
+func AddDNSSEC(token string, domain string, ds DSRecord) (DSRecord, error) {
+	return newDefaultClient(token).AddDNSSEC(context.Background(), domain, ds)
+}
+
+// RemoveDNSSEC removes a DS record from a given domain
+func (c *Client) RemoveDNSSEC(
+	ctx context.Context, domain string, id string,
+) error {
+	params := map[string]interface{}{
+		"domain": domain,
+		"id":     id,
+	}
+
+	_, err := c.Request(ctx, "remove-dnssec", params)
+	return err
+}
+
+// RemoveDNSSEC is a wrapper around Client.RemoveDNSSEC for the given token.
+func RemoveDNSSEC(token string, domain string, id string) error {
+	return newDefaultClient(token).RemoveDNSSEC(context.Background(), domain, id)
+}
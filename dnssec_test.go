@@ -0,0 +1,208 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+// DNSKEY example from RFC 4034 section 5.4
+const testDNSKEY = `dskey.example.com. 86400 IN DNSKEY 256 3 5 ( AQOeiiR0GOMYkDshWoSKz9Xz
+	fwJr1AYtsmx3TGkJaNXVbfi/
+	2pHm822aJ5iI9BMzNXxeYCmZ
+	DRD99WYwYqUSdjMmmAphXdvx
+	egXd/M5+X7OrzKBaMbCVdFLU
+	Uh6DhweJBjEVv5f2wwjM9Xzc
+	nOf+EPbtG9DMBmADjFDc2w/r
+	ljwvFw==
+	) ;  key id = 60485`
+
+func TestListDNSSECExpected(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"dnssec": [
+				{
+					"id": "1337",
+					"key_tag": 60485,
+					"algorithm": 5,
+					"digest_type": 1,
+					"digest": "2BB183AF5F22588179A53B0A98631FAD1A292118"
+				}
+			]
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	records, err := ListDNSSEC(token, domain)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []DSRecord{
+		{
+			ID:         "1337",
+			KeyTag:     60485,
+			Algorithm:  5,
+			DigestType: 1,
+			Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+		},
+	}
+
+	assert.Equal(t, records, expected)
+}
+
+func TestListDNSSECError(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 0,
+			"message": "Testing error"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	records, err := ListDNSSEC(token, domain)
+	assert.Nil(t, records)
+	assert.Error(t, err)
+}
+
+func TestAddDNSSECExpected(t *testing.T) {
+	client, sent := newResponsesClient(t, `{
+		"jsonrpc": "2.0",
+		"result": {
+			"id": "1337",
+			"key_tag": 60485,
+			"algorithm": 5,
+			"digest_type": 1,
+			"digest": "2BB183AF5F22588179A53B0A98631FAD1A292118"
+		}
+	}`)
+
+	ds := DSRecord{
+		KeyTag:     60485,
+		Algorithm:  5,
+		DigestType: 1,
+		Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+	}
+	added, err := client.AddDNSSEC(context.Background(), "testing.com", ds)
+	assert.Nil(t, err)
+
+	ds.ID = "1337"
+	assert.Equal(t, ds, added)
+	assert.Equal(t, "add-dnssec", (*sent)[0].Method)
+	assert.Equal(t, float64(60485), (*sent)[0].Params["key_tag"])
+}
+
+func TestAddDNSSECInvalid(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"jsonrpc": "2.0", "result": {}}`)
+
+	_, err := client.AddDNSSEC(context.Background(), "testing.com", DSRecord{
+		KeyTag:     60485,
+		Algorithm:  9,
+		DigestType: 1,
+		Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+	})
+	assert.Error(t, err)
+	assert.Empty(t, *sent)
+}
+
+func TestDSRecordValidate(t *testing.T) {
+	valid := DSRecord{
+		KeyTag:     60485,
+		Algorithm:  13,
+		DigestType: 2,
+		Digest: "d4b7d520e7bb5f0f67674a0cceb1e3e0614b93c4f9e99b83" +
+			"83f6a1e4469da50a",
+	}
+	assert.Nil(t, valid.Validate())
+
+	invalid := []DSRecord{
+		{Algorithm: 0, DigestType: 2, Digest: valid.Digest},
+		{Algorithm: 253, DigestType: 2, Digest: valid.Digest},
+		{Algorithm: 13, DigestType: 0, Digest: valid.Digest},
+		{Algorithm: 13, DigestType: 7, Digest: valid.Digest},
+		{Algorithm: 13, DigestType: 1, Digest: valid.Digest},
+		{Algorithm: 13, DigestType: 2, Digest: "not-hex"},
+	}
+	for _, ds := range invalid {
+		assert.Error(t, ds.Validate(), ds)
+	}
+}
+
+func TestDSFromDNSKEY(t *testing.T) {
+	// Expected DS records from RFC 4034 section 5.4, and RFC 4509 section 2.3
+	sha1, err := DSFromDNSKEY(testDNSKEY, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, DSRecord{
+		KeyTag:     60485,
+		Algorithm:  5,
+		DigestType: 1,
+		Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+	}, sha1)
+
+	sha256, err := DSFromDNSKEY(testDNSKEY, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, DSRecord{
+		KeyTag:     60485,
+		Algorithm:  5,
+		DigestType: 2,
+		Digest: "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B83" +
+			"83F6A1E4469DA50A",
+	}, sha256)
+	assert.Nil(t, sha256.Validate())
+
+	sha384, err := DSFromDNSKEY(testDNSKEY, 4)
+	assert.Nil(t, err)
+	assert.Nil(t, sha384.Validate())
+}
+
+func TestDSFromDNSKEYInvalid(t *testing.T) {
+	_, err := DSFromDNSKEY(testDNSKEY, 3)
+	assert.Error(t, err)
+
+	invalid := []string{
+		"",
+		"DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9Xz",
+		"example.com. IN DNSKEY 256 3 5",
+		"example.com. IN DNSKEY 0 3 5 AQOeiiR0GOMYkDshWoSKz9Xz",
+		"example.com. IN DNSKEY 256 2 5 AQOeiiR0GOMYkDshWoSKz9Xz",
+		"example.com. IN DNSKEY 256 3 9 AQOeiiR0GOMYkDshWoSKz9Xz",
+		"example.com. IN DNSKEY 256 3 5 not-base64!",
+		"3600 IN DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9Xz",
+		"IN DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9Xz",
+		"hs 3600 DNSKEY 256 3 5 AQOeiiR0GOMYkDshWoSKz9Xz",
+	}
+	for _, dnskey := range invalid {
+		_, err := DSFromDNSKEY(dnskey, 2)
+		assert.Error(t, err, dnskey)
+	}
+}
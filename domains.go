@@ -1,32 +1,70 @@
 package gonjalla
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 )
 
 // Domain struct contains data returned by `list-domains` and `get-domains`
 type Domain struct {
-	Name           string    `json:"name"`
-	Status         string    `json:"status"`
-	Expiry         time.Time `json:"expiry"`
-	Locked         *bool     `json:"locked,omitempty"`
-	Mailforwarding *bool     `json:"mailforwarding,omitempty"`
-	MaxNameservers *int      `json:"max_nameservers,omitempty"`
+	Name           string       `json:"name"`
+	Status         DomainStatus `json:"status"`
+	Expiry         time.Time    `json:"expiry"`
+	Locked         *bool        `json:"locked,omitempty"`
+	Mailforwarding *bool        `json:"mailforwarding,omitempty"`
+	DNSSEC         *bool        `json:"dnssec,omitempty"`
+	Nameservers    []string     `json:"nameservers,omitempty"`
+	MaxNameservers *int         `json:"max_nameservers,omitempty"`
+}
+
+// DomainSettings contains the settings to change with EditDomain.
+// Only the fields that are set are sent, the rest are left as they are.
+type DomainSettings struct {
+	// Locked enables or disables the transfer lock
+	Locked *bool
+	// Mailforwarding enables or disables mail forwarding
+	Mailforwarding *bool
+	// DNSSEC enables or disables DNSSEC
+	DNSSEC *bool
+	// Nameservers replaces the domain's nameservers if not nil. An empty,
+	// non-nil slice resets them to Njalla's own.
+	Nameservers []string
+}
+
+// params returns the `edit-domain` params for the fields that are set
+func (s DomainSettings) params() map[string]interface{} {
+	params := map[string]interface{}{}
+	if s.Locked != nil {
+		params["locked"] = *s.Locked
+	}
+	if s.Mailforwarding != nil {
+		params["mailforwarding"] = *s.Mailforwarding
+	}
+	if s.DNSSEC != nil {
+		params["dnssec"] = *s.DNSSEC
+	}
+	if s.Nameservers != nil {
+		params["nameservers"] = s.Nameservers
+	}
+
+	return params
 }
 
 // Domain availability and price data returned by `find-domains`
 type MarketDomain struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
-	Price  int    `json:"price"`
+	Name   string       `json:"name"`
+	Status MarketStatus `json:"status"`
+	Price  int          `json:"price"`
 }
 
 // ListDomains returns a listing of domains with minimal data
-func ListDomains(token string) ([]Domain, error) {
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
 	params := map[string]interface{}{}
 
-	data, err := Request(token, "list-domains", params)
+	data, err := c.Request(ctx, "list-domains", params)
 	if err != nil {
 		return nil, err
 	}
@@ -44,13 +82,48 @@ func ListDomains(token string) ([]Domain, error) {
 	return response.Domains, nil
 }
 
+// ListDomains is a wrapper around Client.ListDomains for the given token.
+func ListDomains(token string) ([]Domain, error) {
+	return newDefaultClient(token).ListDomains(context.Background())
+}
+
 // GetDomain returns detailed information for each domain
-func GetDomain(token string, domain string) (Domain, error) {
+func (c *Client) GetDomain(ctx context.Context, domain string) (Domain, error) {
 	params := map[string]interface{}{
 		"domain": domain,
 	}
 
-	data, err := Request(token, "get-domain", params)
+	data, err := c.Request(ctx, "get-domain", params)
+	if err != nil {
+		return Domain{}, err
+	}
+
+	var domainStruct Domain
+	err = json.Unmarshal(data, &domainStruct)
+	if err != nil {
+		return Domain{}, err
+	}
+
+	return domainStruct, nil
+}
+
+// GetDomain is a wrapper around Client.GetDomain for the given token.
+func GetDomain(token string, domain string) (Domain, error) {
+	return newDefaultClient(token).GetDomain(context.Background(), domain)
+}
+
+// EditDomain changes the settings of a domain. Only the fields set in
+// `settings` are changed. Returns the updated domain.
+func (c *Client) EditDomain(
+	ctx context.Context, domain string, settings DomainSettings,
+) (Domain, error) {
+	params := settings.params()
+	if len(params) == 0 {
+		return Domain{}, errors.New("no domain settings to change")
+	}
+	params["domain"] = domain
+
+	data, err := c.Request(ctx, "edit-domain", params)
 	if err != nil {
 		return Domain{}, err
 	}
@@ -64,15 +137,24 @@ func GetDomain(token string, domain string) (Domain, error) {
 	return domainStruct, nil
 }
 
+// EditDomain is a wrapper around Client.EditDomain for the given token.
+func EditDomain(
+	token string, domain string, settings DomainSettings,
+) (Domain, error) {
+	return newDefaultClient(token).EditDomain(
+		context.Background(), domain, settings,
+	)
+}
+
 // FindDomains returns availability and price information for a query.
 // If query was `example`, then it'd show availability and price of
 // domains `example.com`, `example.net`, etc.
-func FindDomains(token string, query string) ([]MarketDomain, error) {
+func (c *Client) FindDomains(ctx context.Context, query string) ([]MarketDomain, error) {
 	params := map[string]interface{}{
 		"query": query,
 	}
 
-	data, err := Request(token, "find-domains", params)
+	data, err := c.Request(ctx, "find-domains", params)
 	if err != nil {
 		return nil, err
 	}
@@ -90,66 +172,124 @@ func FindDomains(token string, query string) ([]MarketDomain, error) {
 	return response.Domains, nil
 }
 
-// Checks the task status given a task id
-func CheckTask(token string, id string) (string, error) {
+// FindDomains is a wrapper around Client.FindDomains for the given token.
+func FindDomains(token string, query string) ([]MarketDomain, error) {
+	return newDefaultClient(token).FindDomains(context.Background(), query)
+}
+
+// RegisterDomain registers a domain given a domain name and desired term
+// length. Registration happens asynchronously: the returned Task can be
+// waited on, or persisted and resumed later with Client.ResumeTask.
+func (c *Client) RegisterDomain(
+	ctx context.Context, domain string, years int,
+) (*Task, error) {
 	params := map[string]interface{}{
-		"id": id,
+		"domain": domain,
+		"years":  years,
 	}
 
-	data, err := Request(token, "check-task", params)
+	data, err := c.Request(ctx, "register-domain", params)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	type Response struct {
-		id     string `json:"id"`
-		status string `json:"status"`
-	}
+	return c.decodeTask(data, "register-domain")
+}
 
-	var response Response
-	err = json.Unmarshal(data, &response)
+// RegisterDomain is a wrapper around Client.RegisterDomain for the given
+// token. Unlike the Client method, it blocks until the registration is done,
+// for up to an hour.
+func RegisterDomain(token string, domain string, years int) error {
+	ctx := context.Background()
+
+	task, err := newDefaultClient(token).RegisterDomain(ctx, domain, years)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return response.status, nil
+	_, err = task.Wait(ctx, WaitOptions{Timeout: blockingTaskTimeout})
+	return err
 }
 
-// Registers a domain given a domain name and desired term length
-func RegisterDomain(token string, domain string, years int) error {
+// Longest term a domain can be registered or renewed for, as per ICANN
+const maxDomainYears = 10
+
+// RenewDomain renews a domain for the given number of years. Like
+// registration, renewal happens asynchronously: the returned Task can be
+// waited on, or persisted and resumed later with Client.ResumeTask.
+func (c *Client) RenewDomain(
+	ctx context.Context, domain string, years int,
+) (*Task, error) {
+	if years < 1 || years > maxDomainYears {
+		return nil, fmt.Errorf(
+			"years must be between 1 and %d, got %d", maxDomainYears, years,
+		)
+	}
+
 	params := map[string]interface{}{
 		"domain": domain,
 		"years":  years,
 	}
 
-	data, err := Request(token, "register-domain", params)
+	data, err := c.Request(ctx, "renew-domain", params)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	type Response struct {
-		task string `json:"task"`
-	}
+	return c.decodeTask(data, "renew-domain")
+}
 
-	var response Response
-	err = json.Unmarshal(data, &response)
+// RenewDomain is a wrapper around Client.RenewDomain for the given token.
+// Unlike the Client method, it blocks until the renewal is done, for up to
+// an hour.
+func RenewDomain(token string, domain string, years int) error {
+	ctx := context.Background()
+
+	task, err := newDefaultClient(token).RenewDomain(ctx, domain, years)
 	if err != nil {
 		return err
 	}
 
-	var status string
-	for true {
-		status, err = CheckTask(token, response.task)
-		if err != nil {
-			return err
-		}
+	_, err = task.Wait(ctx, WaitOptions{Timeout: blockingTaskTimeout})
+	return err
+}
 
-		if status == "active" {
-			break
-		}
+// RenewalQuote is the price of renewing a domain, as returned by
+// RenewalPrice
+type RenewalQuote struct {
+	Domain       string
+	Years        int
+	PricePerYear int
+	Total        int
+	Currency     string
+}
+
+// RenewalPrice returns what renewing a domain for the given number of years
+// would cost, without renewing it, based on the renewal price of the
+// domain's TLD.
+func (c *Client) RenewalPrice(
+	ctx context.Context, domain string, years int,
+) (RenewalQuote, error) {
+	name, err := tldOf(domain)
+	if err != nil {
+		return RenewalQuote{}, err
+	}
 
-		time.Sleep(5 * time.Second)
+	tld, err := c.GetTLD(ctx, name)
+	if err != nil {
+		return RenewalQuote{}, err
+	}
+
+	err = tld.ValidateYears(years)
+	if err != nil {
+		return RenewalQuote{}, err
 	}
 
-	return nil
+	return RenewalQuote{
+		Domain:       domain,
+		Years:        years,
+		PricePerYear: tld.RenewalPrice,
+		Total:        tld.RenewalPrice * years,
+		Currency:     tld.Currency,
+	}, nil
 }
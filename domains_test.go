@@ -2,6 +2,8 @@ package gonjalla
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -14,7 +16,7 @@ import (
 
 func TestListDomainsExpected(t *testing.T) {
 	token := "test-token"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -67,7 +69,7 @@ func TestListDomainsExpected(t *testing.T) {
 
 func TestListDomainsError(t *testing.T) {
 	token := "test-token"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -93,7 +95,7 @@ func TestListDomainsError(t *testing.T) {
 func TestGetDomainExpected(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -140,7 +142,7 @@ func TestGetDomainExpected(t *testing.T) {
 func TestGetDomainError(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -165,7 +167,7 @@ func TestGetDomainError(t *testing.T) {
 func TestFindDomainsExpected(t *testing.T) {
 	token := "test-token"
 	query := "testing"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"result": {
@@ -237,7 +239,7 @@ func TestFindDomainsExpected(t *testing.T) {
 func TestFindDomainsError(t *testing.T) {
 	token := "test-token"
 	query := "testing"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -259,3 +261,178 @@ func TestFindDomainsError(t *testing.T) {
 	assert.Nil(t, domains)
 	assert.Error(t, err)
 }
+
+func TestEditDomainExpected(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"name": "testing.com",
+			"status": "active",
+			"expiry": "2021-02-20T19:38:48Z",
+			"locked": false,
+			"mailforwarding": true,
+			"dnssec": false,
+			"max_nameservers": 10
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	var sent map[string]interface{}
+	mocks.GetDoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		json.Unmarshal(body, &sent)
+
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	locked := false
+	result, err := EditDomain(token, domain, DomainSettings{Locked: &locked})
+	if err != nil {
+		t.Error(err)
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"method": "edit-domain",
+		"params": map[string]interface{}{
+			"domain": "testing.com",
+			"locked": false,
+		},
+	}, sent)
+
+	expectedTime, _ := time.Parse(time.RFC3339, "2021-02-20T19:38:48Z")
+	mailforwarding := true
+	dnssec := false
+	maxNameservers := 10
+
+	expected := Domain{
+		Name:           domain,
+		Status:         "active",
+		Expiry:         expectedTime,
+		Locked:         &locked,
+		Mailforwarding: &mailforwarding,
+		DNSSEC:         &dnssec,
+		MaxNameservers: &maxNameservers,
+	}
+
+	assert.Equal(t, result, expected)
+}
+
+func TestEditDomainNoSettings(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		t.Error("request should not be sent")
+		return nil, nil
+	}
+
+	_, err := EditDomain(token, domain, DomainSettings{})
+	assert.Error(t, err)
+}
+
+func TestEditDomainError(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 0,
+			"message": "Testing error"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	dnssec := true
+	_, err := EditDomain(token, domain, DomainSettings{DNSSEC: &dnssec})
+	assert.Error(t, err)
+}
+
+func TestRenewDomainExpected(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+
+	responses := []string{
+		`{"jsonrpc": "2.0", "result": {"task": "task-1337"}}`,
+		`{"jsonrpc": "2.0", "result": {"id": "task-1337", "status": "active"}}`,
+	}
+
+	var methods []string
+	DefaultHTTPClient = &mocks.MockClient{}
+	mocks.GetDoFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		var sent request
+		json.Unmarshal(body, &sent)
+		methods = append(methods, sent.Method)
+
+		testData := responses[0]
+		responses = responses[1:]
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(testData))),
+		}, nil
+	}
+
+	err := RenewDomain(token, domain, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"renew-domain", "check-task"}, methods)
+}
+
+func TestRenewDomainInvalidYears(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		t.Error("request should not be sent")
+		return nil, nil
+	}
+
+	assert.Error(t, RenewDomain(token, domain, 0))
+	assert.Error(t, RenewDomain(token, domain, 11))
+}
+
+func TestRenewalPriceExpected(t *testing.T) {
+	client, sent := newResponsesClient(t, `{
+		"jsonrpc": "2.0",
+		"result": {
+			"tld": "com",
+			"registration_price": 15,
+			"renewal_price": 45,
+			"transfer_price": 15,
+			"currency": "EUR",
+			"min_years": 1,
+			"max_years": 5
+		}
+	}`)
+
+	quote, err := client.RenewalPrice(context.Background(), "testing.com", 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"get-tld"}, methodsOf(*sent))
+	assert.Equal(t, RenewalQuote{
+		Domain:       "testing.com",
+		Years:        3,
+		PricePerYear: 45,
+		Total:        135,
+		Currency:     "EUR",
+	}, quote)
+
+	_, err = client.RenewalPrice(context.Background(), "testing.com", 6)
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package gonjalla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotFound matches API errors about missing domains, records, etc.
+	ErrNotFound = errors.New("not found")
+	// ErrPermissionDenied matches API errors about invalid tokens or
+	// objects that belong to another account.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrValidation matches API errors about invalid or missing parameters.
+	ErrValidation = errors.New("validation failed")
+)
+
+// Maps the JSON-RPC error codes returned by Njalla to the sentinel errors
+// above. Njalla mostly reuses HTTP status codes, plus the standard JSON-RPC
+// codes for malformed requests.
+var apiErrorKinds = map[int]error{
+	400:    ErrValidation,
+	401:    ErrPermissionDenied,
+	403:    ErrPermissionDenied,
+	404:    ErrNotFound,
+	422:    ErrValidation,
+	-32600: ErrValidation,
+	-32602: ErrValidation,
+}
+
+// APIError is returned when Njalla answers a call with a JSON-RPC error
+// object instead of a result.
+type APIError struct {
+	// Code is the JSON-RPC error code
+	Code int
+	// Message is the human readable error message
+	Message string
+	// Method is the API method that was called, like `get-domain`
+	Method string
+	// Raw is the error object exactly as returned by the API
+	Raw json.RawMessage
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s (code %d)", e.Method, e.Message, e.Code)
+}
+
+// Is reports whether the error belongs to the kind of one of the sentinel
+// errors (ErrNotFound, ErrPermissionDenied, ErrValidation), so that
+// `errors.Is(err, gonjalla.ErrNotFound)` works on wrapped API errors.
+func (e *APIError) Is(target error) bool {
+	kind, ok := apiErrorKinds[e.Code]
+	return ok && kind == target
+}
+
+// newAPIError builds an APIError out of the `error` member of a response.
+func newAPIError(method string, raw json.RawMessage) *APIError {
+	apiErr := &APIError{Method: method, Raw: raw}
+
+	var payload struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	err := json.Unmarshal(raw, &payload)
+	if err != nil {
+		apiErr.Message = string(raw)
+		return apiErr
+	}
+
+	apiErr.Code = payload.Code
+	apiErr.Message = payload.Message
+	return apiErr
+}
+
+// Longest body snippet kept in an HTTPError
+const maxErrorBodySnippet = 512
+
+// HTTPError is returned when the API answers with a non-2xx status code, or
+// with a body that isn't JSON (like the HTML error pages of a proxy).
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Header contains the headers of the response
+	Header http.Header
+	// Body is the start of the response body, truncated to a few hundred
+	// bytes
+	Body string
+	// Method is the API method that was called, like `get-domain`
+	Method string
+	// Err is the underlying error, if any. Either the JSON decoding error,
+	// or an *APIError if the response did contain a JSON-RPC error.
+	Err error
+}
+
+func newHTTPError(
+	method string, resp *http.Response, body []byte, err error,
+) *HTTPError {
+	snippet := body
+	if len(snippet) > maxErrorBodySnippet {
+		snippet = snippet[:maxErrorBodySnippet]
+	}
+
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       string(snippet),
+		Method:     method,
+		Err:        err,
+	}
+}
+
+func (e *HTTPError) Error() string {
+	message := fmt.Sprintf(
+		"%s: unexpected HTTP response %d %s",
+		e.Method, e.StatusCode, http.StatusText(e.StatusCode),
+	)
+	if e.Err != nil {
+		message = fmt.Sprintf("%s: %s", message, e.Err)
+	}
+	return message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the request may succeed if sent again, which is
+// the case for server errors (5xx) and rate limiting (429).
+func (e *HTTPError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsRetryable reports whether err is an HTTPError that is worth retrying
+func IsRetryable(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.Retryable()
+}
+
+// IsNotFound reports whether err is an API error about a missing object
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsPermissionDenied reports whether err is an API error about missing
+// permissions
+func IsPermissionDenied(err error) bool {
+	return errors.Is(err, ErrPermissionDenied)
+}
+
+// IsValidation reports whether err is an API error about invalid parameters
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
@@ -0,0 +1,134 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestRequestAPIError(t *testing.T) {
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 404,
+			"message": "Domain not found"
+		}
+	}`
+
+	client, err := NewClient("test-token", WithHTTPClient(&mocks.MockClient{
+		DoFunc: func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body: ioutil.NopCloser(
+					bytes.NewReader([]byte(testData)),
+				),
+			}, nil
+		},
+	}))
+	assert.Nil(t, err)
+
+	_, err = client.GetDomain(context.Background(), "testing.com")
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 404, apiErr.Code)
+	assert.Equal(t, "Domain not found", apiErr.Message)
+	assert.Equal(t, "get-domain", apiErr.Method)
+	assert.JSONEq(
+		t, `{"code": 404, "message": "Domain not found"}`, string(apiErr.Raw),
+	)
+
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsPermissionDenied(err))
+	assert.False(t, IsValidation(err))
+}
+
+func TestAPIErrorKinds(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected error
+	}{
+		{400, ErrValidation},
+		{-32602, ErrValidation},
+		{401, ErrPermissionDenied},
+		{403, ErrPermissionDenied},
+		{404, ErrNotFound},
+	}
+
+	for _, test := range tests {
+		err := fmt.Errorf("wrapped: %w", &APIError{Code: test.code})
+		assert.ErrorIs(t, err, test.expected)
+	}
+
+	err := &APIError{Code: 0, Message: "Testing error"}
+	assert.False(t, IsNotFound(err))
+	assert.False(t, IsPermissionDenied(err))
+	assert.False(t, IsValidation(err))
+}
+
+// newStatusClient returns a Client answering every request with the given
+// status code and body
+func newStatusClient(t *testing.T, status int, body string) *Client {
+	client, _ := newMockClient(t, func(request, int) (int, string) {
+		return status, body
+	})
+	return client
+}
+
+func TestRequestHTTPErrorHTML(t *testing.T) {
+	body := strings.Repeat("<p>Bad Gateway</p>", 100)
+	client := newStatusClient(t, 502, body)
+
+	_, err := client.ListDomains(context.Background())
+
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, 502, httpErr.StatusCode)
+	assert.Equal(t, "text/html", httpErr.Header.Get("Content-Type"))
+	assert.Equal(t, "list-domains", httpErr.Method)
+	assert.Equal(t, body[:maxErrorBodySnippet], httpErr.Body)
+	assert.True(t, httpErr.Retryable())
+	assert.True(t, IsRetryable(err))
+}
+
+func TestRequestHTTPErrorRateLimited(t *testing.T) {
+	client := newStatusClient(t, 429, "Too Many Requests")
+
+	_, err := client.ListDomains(context.Background())
+	assert.True(t, IsRetryable(err))
+}
+
+func TestRequestHTTPErrorWithAPIError(t *testing.T) {
+	client := newStatusClient(
+		t, 403, `{"error": {"code": 403, "message": "Permission denied"}}`,
+	)
+
+	_, err := client.ListDomains(context.Background())
+
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.False(t, httpErr.Retryable())
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.True(t, IsPermissionDenied(err))
+}
+
+func TestRequestNonJSONSuccess(t *testing.T) {
+	client := newStatusClient(t, 200, "<html></html>")
+
+	_, err := client.ListDomains(context.Background())
+
+	var httpErr *HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.False(t, IsRetryable(err))
+}
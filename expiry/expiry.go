@@ -0,0 +1,354 @@
+// Package expiry watches the expiry dates of the domains in a Njalla account,
+// and notifies when they get close.
+//
+// A Monitor sorts each domain into a renewal window, like "expires within 30
+// days", and sends an Event to its Notifier whenever a domain moves into a
+// different window. It can also renew domains automatically once they enter
+// the last window.
+package expiry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sighery/gonjalla"
+)
+
+// Expired is the name of the window of domains past their expiry date
+const Expired = "expired"
+
+// DomainService is the part of gonjalla.Client used by a Monitor
+type DomainService interface {
+	ListDomains(ctx context.Context) ([]gonjalla.Domain, error)
+	RenewDomain(
+		ctx context.Context, domain string, years int,
+	) (*gonjalla.Task, error)
+}
+
+// Window is a period before a domain's expiry date
+type Window struct {
+	// Name identifies the window in events, like "30d"
+	Name string
+	// Before is how long before the expiry date the window starts
+	Before time.Duration
+}
+
+// DefaultWindows returns windows starting 90, 30 and 7 days before expiry
+func DefaultWindows() []Window {
+	return []Window{
+		{Name: "90d", Before: 90 * 24 * time.Hour},
+		{Name: "30d", Before: 30 * 24 * time.Hour},
+		{Name: "7d", Before: 7 * 24 * time.Hour},
+	}
+}
+
+// Event is sent when a domain moves into a different window
+type Event struct {
+	Domain string
+	Expiry time.Time
+	// Window is the name of the window the domain is in now. It's empty if
+	// the domain isn't in any, like after being renewed, and Expired if its
+	// expiry date has passed.
+	Window string
+	// Previous is the window the domain was in, empty on the first scan
+	Previous string
+	// Remaining is the time left until the expiry date, negative once
+	// expired
+	Remaining time.Duration
+	// Renewal is the renewal task started for the domain, if any
+	Renewal *gonjalla.Task
+	// RenewalErr is the error returned when starting the renewal, if any
+	RenewalErr error
+}
+
+// Notifier is sent the events of a Monitor
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(ctx context.Context, event Event) error
+
+// Notify calls f(ctx, event)
+func (f NotifierFunc) Notify(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// RenewPolicy controls automatic renewals
+type RenewPolicy struct {
+	// Years is how long to renew domains for. Defaults to 1.
+	Years int
+	// Filter, if set, limits renewals to the domains it returns true for
+	Filter func(domain gonjalla.Domain) bool
+}
+
+// Monitor scans the domains of an account for upcoming expiry dates. It keeps
+// the window each domain was last seen in, so events are only sent on
+// changes. It's safe for concurrent use.
+type Monitor struct {
+	service  DomainService
+	notifier Notifier
+	windows  []Window
+	renew    *RenewPolicy
+	onError  func(error)
+	now      func() time.Time
+
+	mu    sync.Mutex
+	state map[string]string
+}
+
+// Option configures a Monitor when passed to NewMonitor
+type Option func(*Monitor) error
+
+// NewMonitor returns a Monitor listing domains from the given service, which
+// is usually a *gonjalla.Client, and sending events to the given notifier.
+// Without options it uses DefaultWindows and doesn't renew anything.
+func NewMonitor(
+	service DomainService, notifier Notifier, opts ...Option,
+) (*Monitor, error) {
+	if service == nil {
+		return nil, errors.New("domain service must not be nil")
+	}
+	if notifier == nil {
+		return nil, errors.New("notifier must not be nil")
+	}
+
+	monitor := &Monitor{
+		service:  service,
+		notifier: notifier,
+		windows:  sortWindows(DefaultWindows()),
+		now:      time.Now,
+		state:    map[string]string{},
+	}
+	for _, opt := range opts {
+		err := opt(monitor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return monitor, nil
+}
+
+// WithWindows sets the windows domains are sorted into, in any order
+func WithWindows(windows ...Window) Option {
+	return func(m *Monitor) error {
+		if len(windows) == 0 {
+			return errors.New("at least one window is required")
+		}
+
+		seen := map[string]bool{}
+		for _, window := range windows {
+			if window.Name == "" || window.Name == Expired {
+				return fmt.Errorf("invalid window name %q", window.Name)
+			}
+			if seen[window.Name] {
+				return fmt.Errorf("duplicate window name %q", window.Name)
+			}
+			if window.Before <= 0 {
+				return fmt.Errorf("window %q must start before expiry", window.Name)
+			}
+			seen[window.Name] = true
+		}
+
+		m.windows = sortWindows(windows)
+		return nil
+	}
+}
+
+// WithAutoRenew renews domains when they enter the last window, the one
+// closest to their expiry date. Scans should run more often than the length
+// of that window: a domain that goes straight to Expired between two scans is
+// renewed then instead, which Njalla may refuse once it's past its grace
+// period. Renewals that fail to start are reported in the event and the error
+// of Monitor.Scan, and retried on the next scan.
+//
+// The windows domains were seen in are only kept in memory, so after a
+// restart every domain in the last window looks like it just entered it and
+// is renewed again. Use Monitor.State and WithState to carry them over.
+func WithAutoRenew(policy RenewPolicy) Option {
+	return func(m *Monitor) error {
+		if policy.Years < 0 {
+			return errors.New("renewal years must not be negative")
+		}
+		if policy.Years == 0 {
+			policy.Years = 1
+		}
+		m.renew = &policy
+		return nil
+	}
+}
+
+// WithState seeds the window each domain was last seen in, as returned by
+// Monitor.State before a restart. Domains found in the same window on the
+// first scan are neither sent an event nor renewed again.
+func WithState(state map[string]string) Option {
+	return func(m *Monitor) error {
+		for name, window := range state {
+			m.state[name] = window
+		}
+		return nil
+	}
+}
+
+// WithErrorHandler sets a function called with the errors of the scans done
+// by Monitor.Run, which otherwise ignores them
+func WithErrorHandler(handler func(error)) Option {
+	return func(m *Monitor) error {
+		if handler == nil {
+			return errors.New("error handler must not be nil")
+		}
+		m.onError = handler
+		return nil
+	}
+}
+
+// sortWindows returns a copy of the windows, from the closest to expiry to
+// the furthest
+func sortWindows(windows []Window) []Window {
+	sorted := append([]Window(nil), windows...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Before < sorted[j].Before
+	})
+	return sorted
+}
+
+// classify returns the name of the window a domain expiring in `remaining`
+// is in, or an empty string if it's in none
+func (m *Monitor) classify(remaining time.Duration) string {
+	if remaining <= 0 {
+		return Expired
+	}
+	for _, window := range m.windows {
+		if remaining <= window.Before {
+			return window.Name
+		}
+	}
+	return ""
+}
+
+// Scan lists the domains once, and sends an event for each one that moved
+// into a different window since the last scan. Domains without an expiry
+// date are skipped. Returns the events sent, and the errors of the notifier
+// and of renewals joined together.
+func (m *Monitor) Scan(ctx context.Context) ([]Event, error) {
+	domains, err := m.service.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	seen := make(map[string]bool, len(domains))
+	var events []Event
+	var errs []error
+	for _, domain := range domains {
+		if domain.Expiry.IsZero() {
+			continue
+		}
+		seen[domain.Name] = true
+
+		remaining := domain.Expiry.Sub(now)
+		window := m.classify(remaining)
+		previous, known := m.state[domain.Name]
+		if window == previous && (known || window == "") {
+			continue
+		}
+
+		event := Event{
+			Domain:    domain.Name,
+			Expiry:    domain.Expiry,
+			Window:    window,
+			Previous:  previous,
+			Remaining: remaining,
+		}
+		if m.shouldRenew(domain, window, previous) {
+			event.Renewal, event.RenewalErr = m.service.RenewDomain(
+				ctx, domain.Name, m.renew.Years,
+			)
+		}
+		if event.RenewalErr != nil {
+			// Left in its previous window, so the next scan retries
+			errs = append(
+				errs, fmt.Errorf("renew %s: %w", domain.Name, event.RenewalErr),
+			)
+		} else {
+			m.state[domain.Name] = window
+		}
+
+		events = append(events, event)
+		err := m.notifier.Notify(ctx, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify %s: %w", domain.Name, err))
+		}
+	}
+
+	for name := range m.state {
+		if !seen[name] {
+			delete(m.state, name)
+		}
+	}
+
+	return events, errors.Join(errs...)
+}
+
+// shouldRenew reports whether a domain that just moved into the given window
+// from the previous one must be renewed. That's when it enters the last
+// window, or when it expires without having been renewed there first.
+func (m *Monitor) shouldRenew(
+	domain gonjalla.Domain, window string, previous string,
+) bool {
+	if m.renew == nil {
+		return false
+	}
+	last := m.windows[0].Name
+	if window != last && (window != Expired || previous == last) {
+		return false
+	}
+	return m.renew.Filter == nil || m.renew.Filter(domain)
+}
+
+// State returns the window each domain was last seen in, keyed by domain
+// name. It can be saved and given to WithState when the monitor is created
+// again, so a restart doesn't send the same events or renew domains twice.
+func (m *Monitor) State() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := make(map[string]string, len(m.state))
+	for name, window := range m.state {
+		state[name] = window
+	}
+	return state
+}
+
+// Run scans the domains right away and then every interval, until ctx is
+// done. Scan errors are given to the handler set by WithErrorHandler.
+// Returns the context's error once it's done.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := m.Scan(ctx)
+		if err != nil && m.onError != nil && ctx.Err() == nil {
+			m.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla"
+)
+
+type fakeService struct {
+	domains  []gonjalla.Domain
+	err      error
+	renewErr error
+	renewals []string
+}
+
+func (s *fakeService) ListDomains(ctx context.Context) ([]gonjalla.Domain, error) {
+	return s.domains, s.err
+}
+
+func (s *fakeService) RenewDomain(
+	ctx context.Context, domain string, years int,
+) (*gonjalla.Task, error) {
+	s.renewals = append(s.renewals, domain)
+	if s.renewErr != nil {
+		return nil, s.renewErr
+	}
+	return &gonjalla.Task{ID: "task-" + domain, Method: "renew-domain"}, nil
+}
+
+var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func days(n int) time.Time {
+	return now.Add(time.Duration(n) * 24 * time.Hour)
+}
+
+func newTestMonitor(
+	t *testing.T, service DomainService, opts ...Option,
+) (*Monitor, *[]Event) {
+	var events []Event
+	notifier := NotifierFunc(func(ctx context.Context, event Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	monitor, err := NewMonitor(service, notifier, opts...)
+	assert.Nil(t, err)
+	monitor.now = func() time.Time { return now }
+
+	return monitor, &events
+}
+
+func TestScanClassifies(t *testing.T) {
+	service := &fakeService{domains: []gonjalla.Domain{
+		{Name: "far.com", Expiry: days(200)},
+		{Name: "soon.com", Expiry: days(60)},
+		{Name: "close.com", Expiry: days(20)},
+		{Name: "urgent.com", Expiry: days(3)},
+		{Name: "gone.com", Expiry: days(-1)},
+		{Name: "unknown.com"},
+	}}
+	monitor, sent := newTestMonitor(t, service)
+
+	events, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, events, *sent)
+
+	windows := map[string]string{}
+	for _, event := range events {
+		windows[event.Domain] = event.Window
+	}
+	assert.Equal(t, map[string]string{
+		"soon.com":   "90d",
+		"close.com":  "30d",
+		"urgent.com": "7d",
+		"gone.com":   Expired,
+	}, windows)
+}
+
+func TestScanTransitions(t *testing.T) {
+	service := &fakeService{domains: []gonjalla.Domain{
+		{Name: "example.com", Expiry: days(31)},
+	}}
+	monitor, sent := newTestMonitor(t, service)
+
+	_, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	events, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+
+	now = now.Add(2 * 24 * time.Hour)
+	defer func() { now = now.Add(-2 * 24 * time.Hour) }()
+
+	events, err = monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "30d", events[0].Window)
+	assert.Equal(t, "90d", events[0].Previous)
+
+	service.domains[0].Expiry = days(365)
+	events, err = monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "", events[0].Window)
+	assert.Equal(t, "30d", events[0].Previous)
+	assert.Len(t, *sent, 3)
+}
+
+func TestScanAutoRenew(t *testing.T) {
+	service := &fakeService{domains: []gonjalla.Domain{
+		{Name: "keep.com", Expiry: days(5)},
+		{Name: "drop.com", Expiry: days(5)},
+		{Name: "later.com", Expiry: days(20)},
+	}}
+	monitor, _ := newTestMonitor(t, service, WithAutoRenew(RenewPolicy{
+		Filter: func(domain gonjalla.Domain) bool {
+			return domain.Name != "drop.com"
+		},
+	}))
+
+	events, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"keep.com"}, service.renewals)
+	assert.Equal(t, "task-keep.com", events[0].Renewal.ID)
+	assert.Nil(t, events[1].Renewal)
+
+	_, err = monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"keep.com"}, service.renewals)
+}
+
+func TestScanAutoRenewRetries(t *testing.T) {
+	service := &fakeService{
+		domains:  []gonjalla.Domain{{Name: "keep.com", Expiry: days(5)}},
+		renewErr: errors.New("bad gateway"),
+	}
+	monitor, sent := newTestMonitor(t, service, WithAutoRenew(RenewPolicy{}))
+
+	events, err := monitor.Scan(context.Background())
+	assert.EqualError(t, err, "renew keep.com: bad gateway")
+	assert.Len(t, events, 1)
+	assert.EqualError(t, events[0].RenewalErr, "bad gateway")
+
+	service.renewErr = nil
+	events, err = monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "task-keep.com", events[0].Renewal.ID)
+	assert.Equal(t, []string{"keep.com", "keep.com"}, service.renewals)
+	assert.Len(t, *sent, 2)
+
+	events, err = monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+	assert.Len(t, service.renewals, 2)
+}
+
+func TestScanAutoRenewExpired(t *testing.T) {
+	service := &fakeService{domains: []gonjalla.Domain{
+		{Name: "skipped.com", Expiry: days(20)},
+		{Name: "renewed.com", Expiry: days(5)},
+	}}
+	monitor, _ := newTestMonitor(t, service, WithAutoRenew(RenewPolicy{}))
+
+	_, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"renewed.com"}, service.renewals)
+
+	// Both expire before the next scan, but only one was renewed already
+	service.domains[0].Expiry = days(-1)
+	service.domains[1].Expiry = days(-1)
+	events, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, []string{"renewed.com", "skipped.com"}, service.renewals)
+	assert.Equal(t, "task-skipped.com", events[0].Renewal.ID)
+	assert.Nil(t, events[1].Renewal)
+}
+
+func TestScanWithState(t *testing.T) {
+	service := &fakeService{domains: []gonjalla.Domain{
+		{Name: "urgent.com", Expiry: days(5)},
+		{Name: "close.com", Expiry: days(20)},
+	}}
+	monitor, _ := newTestMonitor(t, service, WithAutoRenew(RenewPolicy{}))
+
+	_, err := monitor.Scan(context.Background())
+	assert.Nil(t, err)
+	state := monitor.State()
+	assert.Equal(t, map[string]string{
+		"urgent.com": "7d",
+		"close.com":  "30d",
+	}, state)
+
+	// A restarted monitor seeded with the state doesn't renew again
+	restarted, sent := newTestMonitor(
+		t, service, WithAutoRenew(RenewPolicy{}), WithState(state),
+	)
+	events, err := restarted.Scan(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, events)
+	assert.Empty(t, *sent)
+	assert.Equal(t, []string{"urgent.com"}, service.renewals)
+}
+
+func TestScanErrors(t *testing.T) {
+	service := &fakeService{err: errors.New("boom")}
+	monitor, _ := newTestMonitor(t, service)
+
+	_, err := monitor.Scan(context.Background())
+	assert.EqualError(t, err, "boom")
+
+	service = &fakeService{domains: []gonjalla.Domain{
+		{Name: "example.com", Expiry: days(5)},
+	}}
+	monitor, err = NewMonitor(service, NotifierFunc(
+		func(ctx context.Context, event Event) error {
+			return errors.New("unreachable")
+		},
+	))
+	assert.Nil(t, err)
+
+	events, err := monitor.Scan(context.Background())
+	assert.Len(t, events, 1)
+	assert.EqualError(t, err, "notify example.com: unreachable")
+}
+
+func TestWithWindowsInvalid(t *testing.T) {
+	service := &fakeService{}
+	notifier := NotifierFunc(func(ctx context.Context, event Event) error {
+		return nil
+	})
+
+	_, err := NewMonitor(service, notifier, WithWindows())
+	assert.NotNil(t, err)
+	_, err = NewMonitor(service, notifier, WithWindows(Window{Name: Expired, Before: time.Hour}))
+	assert.NotNil(t, err)
+	_, err = NewMonitor(service, notifier, WithWindows(Window{Name: "now"}))
+	assert.NotNil(t, err)
+	_, err = NewMonitor(service, notifier, WithWindows(
+		Window{Name: "1d", Before: 24 * time.Hour},
+		Window{Name: "1d", Before: 48 * time.Hour},
+	))
+	assert.NotNil(t, err)
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	service := &fakeService{err: errors.New("boom")}
+	var errs []error
+	monitor, _ := newTestMonitor(t, service, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := monitor.Run(ctx, 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotEmpty(t, errs)
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sighery/gonjalla"
+)
+
+// CallMetrics counts the API calls made by a gonjalla.Client, and how long
+// they took. Its Middleware must be added to the Client when creating it:
+//
+//	calls := exporter.NewCallMetrics()
+//	client, err := gonjalla.NewClient(
+//		"api-token", gonjalla.WithMiddleware(calls.Middleware()),
+//	)
+//
+// It's safe for concurrent use.
+type CallMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*methodStats
+}
+
+type methodStats struct {
+	calls    uint64
+	errors   uint64
+	duration time.Duration
+}
+
+// NewCallMetrics returns an empty CallMetrics
+func NewCallMetrics() *CallMetrics {
+	return &CallMetrics{methods: map[string]*methodStats{}}
+}
+
+// Middleware returns a gonjalla.Middleware recording every call into c
+func (c *CallMetrics) Middleware() gonjalla.Middleware {
+	return func(next gonjalla.RoundTripFunc) gonjalla.RoundTripFunc {
+		return func(
+			ctx context.Context, call *gonjalla.Call,
+		) (json.RawMessage, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			c.record(call.Method, time.Since(start), err)
+			return result, err
+		}
+	}
+}
+
+func (c *CallMetrics) record(method string, duration time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.methods[method]
+	if !ok {
+		stats = &methodStats{}
+		c.methods[method] = stats
+	}
+	stats.calls++
+	stats.duration += duration
+	if err != nil {
+		stats.errors++
+	}
+}
+
+// families returns the call metrics, by method
+func (c *CallMetrics) families() []*family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	calls := &family{
+		name: "njalla_api_calls_total",
+		help: "API calls made, by method.",
+		kind: "counter",
+	}
+	errs := &family{
+		name: "njalla_api_call_errors_total",
+		help: "API calls that failed, by method.",
+		kind: "counter",
+	}
+	durations := &family{
+		name: "njalla_api_call_duration_seconds",
+		help: "Time spent on API calls, by method.",
+		kind: "summary",
+	}
+
+	methods := make([]string, 0, len(c.methods))
+	for method := range c.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		stats := c.methods[method]
+		calls.add(float64(stats.calls), "method", method)
+		errs.add(float64(stats.errors), "method", method)
+		durations.addSuffixed(
+			"_sum", stats.duration.Seconds(), "method", method,
+		)
+		durations.addSuffixed(
+			"_count", float64(stats.calls), "method", method,
+		)
+	}
+
+	return []*family{calls, errs, durations}
+}
@@ -0,0 +1,302 @@
+// Package exporter exposes the inventory of a Njalla account as Prometheus
+// metrics: domain expiry dates and lock states, DNS record counts, and server
+// statuses. It writes the Prometheus text format itself, so it doesn't need
+// the Prometheus client library.
+//
+// The inventory is collected by Exporter.Refresh, usually called in the
+// background by Exporter.Run, and served from memory, so scrapes don't cause
+// API calls.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sighery/gonjalla"
+)
+
+// Service is the part of gonjalla.Client used by an Exporter
+type Service interface {
+	ListDomains(ctx context.Context) ([]gonjalla.Domain, error)
+	ListRecords(ctx context.Context, domain string) ([]gonjalla.Record, error)
+	ListServers(ctx context.Context) ([]gonjalla.Server, error)
+}
+
+// DefaultInterval is how often Exporter.Run refreshes the inventory by
+// default
+const DefaultInterval = 5 * time.Minute
+
+// Exporter is an http.Handler serving the metrics of a Njalla account in the
+// Prometheus text format. It's safe for concurrent use.
+type Exporter struct {
+	service  Service
+	calls    *CallMetrics
+	interval time.Duration
+	onError  func(error)
+	now      func() time.Time
+
+	mu            sync.RWMutex
+	inventory     []*family
+	lastRefresh   time.Time
+	lastSuccess   bool
+	refreshErrors uint64
+}
+
+// Option configures an Exporter when passed to New
+type Option func(*Exporter) error
+
+// New returns an Exporter collecting the inventory from the given service,
+// which is usually a *gonjalla.Client. It serves no inventory metrics until
+// the first refresh.
+func New(service Service, opts ...Option) (*Exporter, error) {
+	if service == nil {
+		return nil, errors.New("service must not be nil")
+	}
+
+	exporter := &Exporter{
+		service:  service,
+		interval: DefaultInterval,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		err := opt(exporter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return exporter, nil
+}
+
+// WithInterval sets how often Exporter.Run refreshes the inventory
+func WithInterval(interval time.Duration) Option {
+	return func(e *Exporter) error {
+		if interval <= 0 {
+			return errors.New("interval must be positive")
+		}
+		e.interval = interval
+		return nil
+	}
+}
+
+// WithCallMetrics serves the API call metrics recorded by the given
+// CallMetrics along with the inventory
+func WithCallMetrics(calls *CallMetrics) Option {
+	return func(e *Exporter) error {
+		if calls == nil {
+			return errors.New("call metrics must not be nil")
+		}
+		e.calls = calls
+		return nil
+	}
+}
+
+// WithErrorHandler sets a function called with the errors of the refreshes
+// done by Exporter.Run, which otherwise only counts them
+func WithErrorHandler(handler func(error)) Option {
+	return func(e *Exporter) error {
+		if handler == nil {
+			return errors.New("error handler must not be nil")
+		}
+		e.onError = handler
+		return nil
+	}
+}
+
+// Refresh collects the inventory of the account. If any call fails, the
+// previous inventory keeps being served and the error is returned.
+func (e *Exporter) Refresh(ctx context.Context) error {
+	inventory, err := e.collect(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastRefresh = e.now()
+	e.lastSuccess = err == nil
+	if err != nil {
+		e.refreshErrors++
+		return err
+	}
+	e.inventory = inventory
+
+	return nil
+}
+
+// collect lists the domains, their records and the servers of the account
+func (e *Exporter) collect(ctx context.Context) ([]*family, error) {
+	domains, err := e.service.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := &family{
+		name: "njalla_domain_expiry_timestamp_seconds",
+		help: "Expiry date of the domain, as a Unix timestamp.",
+		kind: "gauge",
+	}
+	locked := &family{
+		name: "njalla_domain_locked",
+		help: "Whether the domain is locked against transfers.",
+		kind: "gauge",
+	}
+	domainStatus := &family{
+		name: "njalla_domain_status",
+		help: "Status of the domain, as a label.",
+		kind: "gauge",
+	}
+	records := &family{
+		name: "njalla_domain_records",
+		help: "Number of DNS records of the domain, by type.",
+		kind: "gauge",
+	}
+
+	for _, domain := range domains {
+		if !domain.Expiry.IsZero() {
+			expiry.add(
+				float64(domain.Expiry.Unix()), "domain", domain.Name,
+			)
+		}
+		if domain.Locked != nil {
+			locked.add(boolValue(*domain.Locked), "domain", domain.Name)
+		}
+		domainStatus.add(
+			1, "domain", domain.Name, "status", string(domain.Status),
+		)
+
+		domainRecords, err := e.service.ListRecords(ctx, domain.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		counts := map[string]int{}
+		for _, record := range domainRecords {
+			counts[record.Type]++
+		}
+		types := make([]string, 0, len(counts))
+		for recordType := range counts {
+			types = append(types, recordType)
+		}
+		sort.Strings(types)
+		for _, recordType := range types {
+			records.add(
+				float64(counts[recordType]),
+				"domain", domain.Name, "type", recordType,
+			)
+		}
+	}
+
+	servers, err := e.service.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serverStatus := &family{
+		name: "njalla_server_status",
+		help: "Status of the server, as a label.",
+		kind: "gauge",
+	}
+	serverExpiry := &family{
+		name: "njalla_server_expiry_timestamp_seconds",
+		help: "Expiry date of the server, as a Unix timestamp.",
+		kind: "gauge",
+	}
+	autorenew := &family{
+		name: "njalla_server_autorenew",
+		help: "Whether the server renews automatically.",
+		kind: "gauge",
+	}
+
+	for _, server := range servers {
+		serverStatus.add(
+			1, "server", server.Name, "id", server.ID,
+			"status", string(server.Status),
+		)
+		// Servers with an expiry date the API didn't format as RFC 3339
+		// are left out rather than failing the whole refresh
+		expiresAt, err := time.Parse(time.RFC3339, server.Expiry)
+		if err == nil {
+			serverExpiry.add(
+				float64(expiresAt.Unix()), "server", server.Name, "id", server.ID,
+			)
+		}
+		autorenew.add(
+			boolValue(server.Autorenew), "server", server.Name, "id", server.ID,
+		)
+	}
+
+	return []*family{
+		expiry, locked, domainStatus, records,
+		serverStatus, serverExpiry, autorenew,
+	}, nil
+}
+
+// Run refreshes the inventory right away and then on every interval, until
+// ctx is done. Refresh errors are given to the handler set by
+// WithErrorHandler. Returns the context's error once it's done.
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		err := e.Refresh(ctx)
+		if err != nil && e.onError != nil && ctx.Err() == nil {
+			e.onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	families := append([]*family(nil), e.inventory...)
+	families = append(families, e.refreshFamilies()...)
+	e.mu.RUnlock()
+
+	if e.calls != nil {
+		families = append(families, e.calls.families()...)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeFamilies(w, families)
+}
+
+// refreshFamilies returns the metrics about the refreshes themselves. Must
+// be called with e.mu held.
+func (e *Exporter) refreshFamilies() []*family {
+	errs := &family{
+		name: "njalla_exporter_refresh_errors_total",
+		help: "Inventory refreshes that failed.",
+		kind: "counter",
+	}
+	errs.add(float64(e.refreshErrors))
+
+	if e.lastRefresh.IsZero() {
+		return []*family{errs}
+	}
+
+	last := &family{
+		name: "njalla_exporter_last_refresh_timestamp_seconds",
+		help: "When the inventory was last refreshed, as a Unix timestamp.",
+		kind: "gauge",
+	}
+	last.add(float64(e.lastRefresh.Unix()))
+
+	success := &family{
+		name: "njalla_exporter_last_refresh_success",
+		help: "Whether the last inventory refresh succeeded.",
+		kind: "gauge",
+	}
+	success.add(boolValue(e.lastSuccess))
+
+	return []*family{errs, last, success}
+}
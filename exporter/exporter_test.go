@@ -0,0 +1,185 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla"
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+type fakeService struct {
+	domains []gonjalla.Domain
+	records map[string][]gonjalla.Record
+	servers []gonjalla.Server
+	err     error
+}
+
+func (s *fakeService) ListDomains(ctx context.Context) ([]gonjalla.Domain, error) {
+	return s.domains, s.err
+}
+
+func (s *fakeService) ListRecords(
+	ctx context.Context, domain string,
+) ([]gonjalla.Record, error) {
+	return s.records[domain], nil
+}
+
+func (s *fakeService) ListServers(ctx context.Context) ([]gonjalla.Server, error) {
+	return s.servers, nil
+}
+
+func scrape(t *testing.T, exporter *Exporter) string {
+	recorder := httptest.NewRecorder()
+	exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(
+		t, "text/plain; version=0.0.4; charset=utf-8",
+		recorder.Header().Get("Content-Type"),
+	)
+	return recorder.Body.String()
+}
+
+func TestRefreshInventory(t *testing.T) {
+	locked := true
+	service := &fakeService{
+		domains: []gonjalla.Domain{{
+			Name:   "example.com",
+			Status: gonjalla.DomainActive,
+			Expiry: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Locked: &locked,
+		}},
+		records: map[string][]gonjalla.Record{
+			"example.com": {
+				{Type: "A"}, {Type: "A"}, {Type: "MX"},
+			},
+		},
+		servers: []gonjalla.Server{{
+			Name:      "web \"1\"",
+			ID:        "srv-1",
+			Status:    gonjalla.ServerRunning,
+			Expiry:    "2025-06-01T00:00:00Z",
+			Autorenew: true,
+		}},
+	}
+
+	exporter, err := New(service)
+	assert.Nil(t, err)
+	exporter.now = func() time.Time { return time.Unix(1700000000, 0) }
+	assert.Nil(t, exporter.Refresh(context.Background()))
+
+	expected := `# HELP njalla_domain_expiry_timestamp_seconds Expiry date of the domain, as a Unix timestamp.
+# TYPE njalla_domain_expiry_timestamp_seconds gauge
+njalla_domain_expiry_timestamp_seconds{domain="example.com"} 1735689600
+# HELP njalla_domain_locked Whether the domain is locked against transfers.
+# TYPE njalla_domain_locked gauge
+njalla_domain_locked{domain="example.com"} 1
+# HELP njalla_domain_records Number of DNS records of the domain, by type.
+# TYPE njalla_domain_records gauge
+njalla_domain_records{domain="example.com",type="A"} 2
+njalla_domain_records{domain="example.com",type="MX"} 1
+# HELP njalla_domain_status Status of the domain, as a label.
+# TYPE njalla_domain_status gauge
+njalla_domain_status{domain="example.com",status="active"} 1
+# HELP njalla_exporter_last_refresh_success Whether the last inventory refresh succeeded.
+# TYPE njalla_exporter_last_refresh_success gauge
+njalla_exporter_last_refresh_success 1
+# HELP njalla_exporter_last_refresh_timestamp_seconds When the inventory was last refreshed, as a Unix timestamp.
+# TYPE njalla_exporter_last_refresh_timestamp_seconds gauge
+njalla_exporter_last_refresh_timestamp_seconds 1700000000
+# HELP njalla_exporter_refresh_errors_total Inventory refreshes that failed.
+# TYPE njalla_exporter_refresh_errors_total counter
+njalla_exporter_refresh_errors_total 0
+# HELP njalla_server_autorenew Whether the server renews automatically.
+# TYPE njalla_server_autorenew gauge
+njalla_server_autorenew{server="web \"1\"",id="srv-1"} 1
+# HELP njalla_server_expiry_timestamp_seconds Expiry date of the server, as a Unix timestamp.
+# TYPE njalla_server_expiry_timestamp_seconds gauge
+njalla_server_expiry_timestamp_seconds{server="web \"1\"",id="srv-1"} 1748736000
+# HELP njalla_server_status Status of the server, as a label.
+# TYPE njalla_server_status gauge
+njalla_server_status{server="web \"1\"",id="srv-1",status="running"} 1
+`
+	assert.Equal(t, expected, scrape(t, exporter))
+}
+
+func TestRefreshErrorKeepsInventory(t *testing.T) {
+	service := &fakeService{domains: []gonjalla.Domain{
+		{Name: "example.com", Status: gonjalla.DomainActive},
+	}}
+
+	exporter, err := New(service)
+	assert.Nil(t, err)
+	assert.Nil(t, exporter.Refresh(context.Background()))
+
+	service.err = errors.New("boom")
+	assert.EqualError(t, exporter.Refresh(context.Background()), "boom")
+
+	body := scrape(t, exporter)
+	assert.Contains(t, body, `njalla_domain_status{domain="example.com",status="active"} 1`)
+	assert.Contains(t, body, "njalla_exporter_last_refresh_success 0\n")
+	assert.Contains(t, body, "njalla_exporter_refresh_errors_total 1\n")
+}
+
+func TestCallMetrics(t *testing.T) {
+	responses := []string{
+		`{"result": {"domains": []}}`,
+		`{"error": {"code": 500, "message": "oops"}}`,
+	}
+	calls := NewCallMetrics()
+	client, err := gonjalla.NewClient(
+		"test-token",
+		gonjalla.WithMiddleware(calls.Middleware()),
+		gonjalla.WithHTTPClient(&mocks.MockClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				body := responses[0]
+				responses = responses[1:]
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+				}, nil
+			},
+		}),
+	)
+	assert.Nil(t, err)
+
+	_, err = client.ListDomains(context.Background())
+	assert.Nil(t, err)
+	_, err = client.ListDomains(context.Background())
+	assert.NotNil(t, err)
+
+	exporter, err := New(&fakeService{}, WithCallMetrics(calls))
+	assert.Nil(t, err)
+
+	body := scrape(t, exporter)
+	assert.Contains(t, body, "# TYPE njalla_api_call_duration_seconds summary\n")
+	assert.Contains(t, body, `njalla_api_calls_total{method="list-domains"} 2`)
+	assert.Contains(t, body, `njalla_api_call_errors_total{method="list-domains"} 1`)
+	assert.Contains(t, body, `njalla_api_call_duration_seconds_count{method="list-domains"} 2`)
+	assert.NotContains(t, body, "njalla_exporter_last_refresh_success")
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	service := &fakeService{err: errors.New("boom")}
+	var errs []error
+	exporter, err := New(
+		service,
+		WithInterval(10*time.Millisecond),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = exporter.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotEmpty(t, errs)
+}
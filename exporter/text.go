@@ -0,0 +1,109 @@
+package exporter
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// family is a metric family in the Prometheus text exposition format
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+// sample is one line of a family. suffix is appended to the family name, for
+// the `_sum` and `_count` lines of summaries.
+type sample struct {
+	suffix string
+	labels []label
+	value  float64
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+// add appends a sample to the family, with labels given as name, value pairs
+func (f *family) add(value float64, labels ...string) {
+	f.addSuffixed("", value, labels...)
+}
+
+func (f *family) addSuffixed(suffix string, value float64, labels ...string) {
+	s := sample{suffix: suffix, value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.labels = append(s.labels, label{name: labels[i], value: labels[i+1]})
+	}
+	f.samples = append(f.samples, s)
+}
+
+// writeFamilies writes the families in the Prometheus text format, sorted by
+// name. Families without samples are skipped.
+func writeFamilies(w io.Writer, families []*family) error {
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	var b strings.Builder
+	for _, f := range families {
+		if len(f.samples) == 0 {
+			continue
+		}
+
+		b.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+		b.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+		for _, s := range f.samples {
+			b.WriteString(f.name + s.suffix)
+			if len(s.labels) > 0 {
+				b.WriteByte('{')
+				for i, l := range s.labels {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteString(l.name + `="` + escapeLabel(l.value) + `"`)
+				}
+				b.WriteByte('}')
+			}
+			b.WriteString(" " + formatValue(s.value) + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,192 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// Forward contains data returned by `list-forwards`. Mail sent to `From` at
+// the domain is forwarded to the `To` address.
+type Forward struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Validate checks that From is a valid local part (the part before the `@`)
+// and To a valid email address
+func (f Forward) Validate() error {
+	if !validLocalPart(f.From) {
+		return fmt.Errorf("invalid forward source %q", f.From)
+	}
+
+	address, err := mail.ParseAddress(f.To)
+	if err != nil || address.Address != f.To || address.Name != "" {
+		return fmt.Errorf("invalid forward destination %q", f.To)
+	}
+
+	return nil
+}
+
+// key identifies a forward, ignoring case
+func (f Forward) key() string {
+	return strings.ToLower(f.From) + " " + strings.ToLower(f.To)
+}
+
+// validLocalPart reports whether local is a valid unquoted email local
+// part, as per the `dot-atom` rule of RFC 5322
+func validLocalPart(local string) bool {
+	if local == "" || len(local) > 64 {
+		return false
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return false
+		}
+		for _, r := range atom {
+			isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+				(r >= '0' && r <= '9')
+			if !isAlnum && !strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ListForwards returns a listing of all mail forwards for a given domain
+func (c *Client) ListForwards(
+	ctx context.Context, domain string,
+) ([]Forward, error) {
+	params := map[string]interface{}{
+		"domain": domain,
+	}
+
+	data, err := c.Request(ctx, "list-forwards", params)
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Forwards []Forward `json:"forwards"`
+	}
+
+	var response Response
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Forwards, nil
+}
+
+// ListForwards is a wrapper around Client.ListForwards for the given token.
+func ListForwards(token string, domain string) ([]Forward, error) {
+	return newDefaultClient(token).ListForwards(context.Background(), domain)
+}
+
+// AddForward adds a mail forward to a given domain
+func (c *Client) AddForward(
+	ctx context.Context, domain string, forward Forward,
+) error {
+	err := forward.Validate()
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{
+		"domain": domain,
+		"from":   forward.From,
+		"to":     forward.To,
+	}
+
+	_, err = c.Request(ctx, "add-forward", params)
+	return err
+}
+
+// AddForward is a wrapper around Client.AddForward for the given token.
+func AddForward(token string, domain string, forward Forward) error {
+	return newDefaultClient(token).AddForward(
+		context.Background(), domain, forward,
+	)
+}
+
+// RemoveForward removes a mail forward from a given domain
+func (c *Client) RemoveForward(
+	ctx context.Context, domain string, forward Forward,
+) error {
+	params := map[string]interface{}{
+		"domain": domain,
+		"from":   forward.From,
+		"to":     forward.To,
+	}
+
+	_, err := c.Request(ctx, "remove-forward", params)
+	return err
+}
+
+// RemoveForward is a wrapper around Client.RemoveForward for the given token.
+func RemoveForward(token string, domain string, forward Forward) error {
+	return newDefaultClient(token).RemoveForward(
+		context.Background(), domain, forward,
+	)
+}
+
+// SyncForwards makes the mail forwards of a domain match `desired`, adding
+// the missing ones and then removing the ones not in it. Forwards are
+// compared ignoring case. Running it again with the same forwards changes
+// nothing. Returns the forwards that were added and removed, which are
+// partial if an error happened half way.
+func (c *Client) SyncForwards(
+	ctx context.Context, domain string, desired []Forward,
+) (added []Forward, removed []Forward, err error) {
+	wanted := map[string]bool{}
+	for _, forward := range desired {
+		err = forward.Validate()
+		if err != nil {
+			return nil, nil, err
+		}
+		wanted[forward.key()] = true
+	}
+
+	current, err := c.ListForwards(ctx, domain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, forward := range current {
+		existing[forward.key()] = true
+	}
+
+	for _, forward := range desired {
+		if existing[forward.key()] {
+			continue
+		}
+
+		err = c.AddForward(ctx, domain, forward)
+		if err != nil {
+			return added, removed, err
+		}
+		existing[forward.key()] = true
+		added = append(added, forward)
+	}
+
+	for _, forward := range current {
+		if wanted[forward.key()] {
+			continue
+		}
+
+		err = c.RemoveForward(ctx, domain, forward)
+		if err != nil {
+			return added, removed, err
+		}
+		removed = append(removed, forward)
+	}
+
+	return added, removed, nil
+}
@@ -0,0 +1,159 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestListForwardsExpected(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"forwards": [
+				{"from": "info", "to": "someone@example.com"},
+				{"from": "abuse", "to": "security@example.com"}
+			]
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	forwards, err := ListForwards(token, domain)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []Forward{
+		{From: "info", To: "someone@example.com"},
+		{From: "abuse", To: "security@example.com"},
+	}
+
+	assert.Equal(t, forwards, expected)
+}
+
+func TestListForwardsError(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 0,
+			"message": "Testing error"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	forwards, err := ListForwards(token, domain)
+	assert.Nil(t, forwards)
+	assert.Error(t, err)
+}
+
+func TestAddForwardExpected(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"jsonrpc": "2.0", "result": {}}`)
+
+	err := client.AddForward(
+		context.Background(), "testing.com",
+		Forward{From: "info", To: "someone@example.com"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []request{{
+		Method: "add-forward",
+		Params: map[string]interface{}{
+			"domain": "testing.com",
+			"from":   "info",
+			"to":     "someone@example.com",
+		},
+	}}, *sent)
+}
+
+func TestForwardValidate(t *testing.T) {
+	valid := []Forward{
+		{From: "info", To: "someone@example.com"},
+		{From: "first.last+tag", To: "someone@example.com"},
+	}
+	for _, forward := range valid {
+		assert.Nil(t, forward.Validate(), forward)
+	}
+
+	invalid := []Forward{
+		{From: "", To: "someone@example.com"},
+		{From: "info@testing.com", To: "someone@example.com"},
+		{From: ".info", To: "someone@example.com"},
+		{From: "in fo", To: "someone@example.com"},
+		{From: "info", To: ""},
+		{From: "info", To: "someone"},
+		{From: "info", To: "Someone <someone@example.com>"},
+	}
+	for _, forward := range invalid {
+		assert.Error(t, forward.Validate(), forward)
+	}
+}
+
+func TestSyncForwards(t *testing.T) {
+	client, sent := newResponsesClient(
+		t,
+		`{"result": {"forwards": [
+			{"from": "info", "to": "someone@example.com"},
+			{"from": "old", "to": "someone@example.com"}
+		]}}`,
+		`{"result": {}}`,
+	)
+
+	added, removed, err := client.SyncForwards(
+		context.Background(), "testing.com", []Forward{
+			{From: "INFO", To: "someone@example.com"},
+			{From: "abuse", To: "security@example.com"},
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(
+		t, []Forward{{From: "abuse", To: "security@example.com"}}, added,
+	)
+	assert.Equal(
+		t, []Forward{{From: "old", To: "someone@example.com"}}, removed,
+	)
+
+	methods := []string{}
+	for _, single := range *sent {
+		methods = append(methods, single.Method)
+	}
+	assert.Equal(
+		t, []string{"list-forwards", "add-forward", "remove-forward"}, methods,
+	)
+}
+
+func TestSyncForwardsInvalid(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"result": {}}`)
+
+	_, _, err := client.SyncForwards(
+		context.Background(), "testing.com", []Forward{{From: "info"}},
+	)
+	assert.Error(t, err)
+	assert.Empty(t, *sent)
+}
@@ -0,0 +1,158 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// GlueRecord contains data returned by `list-glue`. Glue records give the
+// addresses of nameservers that live under the domain they serve, like
+// `ns1.example.com` for `example.com`.
+type GlueRecord struct {
+	// Name is the nameserver's name under the domain, like `ns1`
+	Name string `json:"name"`
+	// IPv4 is the nameserver's IPv4 address, if any
+	IPv4 string `json:"address4,omitempty"`
+	// IPv6 is the nameserver's IPv6 address, if any
+	IPv6 string `json:"address6,omitempty"`
+}
+
+// Validate checks the name of the glue record, and that it has at least one
+// address, each of the right IP version
+func (g GlueRecord) Validate() error {
+	if g.Name == "" {
+		return errors.New("glue record name must not be empty")
+	}
+	for _, label := range strings.Split(strings.ToLower(g.Name), ".") {
+		if !validLabel(label) {
+			return fmt.Errorf("invalid glue record name %q", g.Name)
+		}
+	}
+
+	if g.IPv4 == "" && g.IPv6 == "" {
+		return fmt.Errorf("glue record %q has no addresses", g.Name)
+	}
+
+	if g.IPv4 != "" {
+		addr, err := netip.ParseAddr(g.IPv4)
+		if err != nil || !addr.Is4() {
+			return fmt.Errorf("invalid IPv4 address %q", g.IPv4)
+		}
+	}
+
+	if g.IPv6 != "" {
+		addr, err := netip.ParseAddr(g.IPv6)
+		if err != nil || !addr.Is6() || addr.Is4In6() || addr.Zone() != "" {
+			return fmt.Errorf("invalid IPv6 address %q", g.IPv6)
+		}
+	}
+
+	return nil
+}
+
+// params returns the API params for a glue record of a given domain
+func (g GlueRecord) params(domain string) map[string]interface{} {
+	params := map[string]interface{}{
+		"domain": domain,
+		"name":   g.Name,
+	}
+	if g.IPv4 != "" {
+		params["address4"] = g.IPv4
+	}
+	if g.IPv6 != "" {
+		params["address6"] = g.IPv6
+	}
+
+	return params
+}
+
+// ListGlue returns a listing of all glue records for a given domain
+func (c *Client) ListGlue(
+	ctx context.Context, domain string,
+) ([]GlueRecord, error) {
+	params := map[string]interface{}{
+		"domain": domain,
+	}
+
+	data, err := c.Request(ctx, "list-glue", params)
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Glue []GlueRecord `json:"glue"`
+	}
+
+	var response Response
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Glue, nil
+}
+
+// ListGlue is a wrapper around Client.ListGlue for the given token.
+func ListGlue(token string, domain string) ([]GlueRecord, error) {
+	return newDefaultClient(token).ListGlue(context.Background(), domain)
+}
+
+// AddGlue adds a glue record to a given domain
+func (c *Client) AddGlue(
+	ctx context.Context, domain string, glue GlueRecord,
+) error {
+	err := glue.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Request(ctx, "add-glue", glue.params(domain))
+	return err
+}
+
+// AddGlue is a wrapper around Client.AddGlue for the given token.
+func AddGlue(token string, domain string, glue GlueRecord) error {
+	return newDefaultClient(token).AddGlue(context.Background(), domain, glue)
+}
+
+// EditGlue changes the addresses of the glue record with the same name.
+// Like EditRecord, it sends all of the glue record's fields, so get the
+// current one from ListGlue first to only change one of its addresses.
+func (c *Client) EditGlue(
+	ctx context.Context, domain string, glue GlueRecord,
+) error {
+	err := glue.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Request(ctx, "edit-glue", glue.params(domain))
+	return err
+}
+
+// EditGlue is a wrapper around Client.EditGlue for the given token.
+func EditGlue(token string, domain string, glue GlueRecord) error {
+	return newDefaultClient(token).EditGlue(context.Background(), domain, glue)
+}
+
+// RemoveGlue removes the glue record with the given name from a domain
+func (c *Client) RemoveGlue(
+	ctx context.Context, domain string, name string,
+) error {
+	params := map[string]interface{}{
+		"domain": domain,
+		"name":   name,
+	}
+
+	_, err := c.Request(ctx, "remove-glue", params)
+	return err
+}
+
+// RemoveGlue is a wrapper around Client.RemoveGlue for the given token.
+func RemoveGlue(token string, domain string, name string) error {
+	return newDefaultClient(token).RemoveGlue(context.Background(), domain, name)
+}
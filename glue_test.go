@@ -0,0 +1,144 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestListGlueExpected(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"glue": [
+				{
+					"name": "ns1",
+					"address4": "192.0.2.1",
+					"address6": "2001:db8::1"
+				},
+				{
+					"name": "ns2",
+					"address4": "192.0.2.2"
+				}
+			]
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	glue, err := ListGlue(token, domain)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []GlueRecord{
+		{Name: "ns1", IPv4: "192.0.2.1", IPv6: "2001:db8::1"},
+		{Name: "ns2", IPv4: "192.0.2.2"},
+	}
+
+	assert.Equal(t, glue, expected)
+}
+
+func TestListGlueError(t *testing.T) {
+	token := "test-token"
+	domain := "testing.com"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 0,
+			"message": "Testing error"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	glue, err := ListGlue(token, domain)
+	assert.Nil(t, glue)
+	assert.Error(t, err)
+}
+
+func TestAddGlueExpected(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"jsonrpc": "2.0", "result": {}}`)
+
+	err := client.AddGlue(
+		context.Background(), "testing.com",
+		GlueRecord{Name: "ns1", IPv6: "2001:db8::1"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []request{{
+		Method: "add-glue",
+		Params: map[string]interface{}{
+			"domain":   "testing.com",
+			"name":     "ns1",
+			"address6": "2001:db8::1",
+		},
+	}}, *sent)
+}
+
+func TestEditGlueInvalid(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"jsonrpc": "2.0", "result": {}}`)
+
+	err := client.EditGlue(
+		context.Background(), "testing.com",
+		GlueRecord{Name: "ns1", IPv4: "2001:db8::1"},
+	)
+	assert.Error(t, err)
+	assert.Empty(t, *sent)
+}
+
+func TestRemoveGlueExpected(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"jsonrpc": "2.0", "result": {}}`)
+
+	err := client.RemoveGlue(context.Background(), "testing.com", "ns1")
+	assert.Nil(t, err)
+	assert.Equal(t, "remove-glue", (*sent)[0].Method)
+}
+
+func TestGlueRecordValidate(t *testing.T) {
+	valid := []GlueRecord{
+		{Name: "ns1", IPv4: "192.0.2.1"},
+		{Name: "ns1", IPv6: "2001:db8::1"},
+		{Name: "ns1.dns", IPv4: "192.0.2.1", IPv6: "2001:db8::1"},
+	}
+	for _, glue := range valid {
+		assert.Nil(t, glue.Validate(), glue)
+	}
+
+	invalid := []GlueRecord{
+		{Name: "", IPv4: "192.0.2.1"},
+		{Name: "ns_1", IPv4: "192.0.2.1"},
+		{Name: "ns1"},
+		{Name: "ns1", IPv4: "192.0.2.256"},
+		{Name: "ns1", IPv4: "2001:db8::1"},
+		{Name: "ns1", IPv6: "192.0.2.1"},
+		{Name: "ns1", IPv6: "::ffff:192.0.2.1"},
+		{Name: "ns1", IPv6: "fe80::1%eth0"},
+	}
+	for _, glue := range invalid {
+		assert.Error(t, glue.Validate(), glue)
+	}
+}
@@ -0,0 +1,170 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// redacted replaces secrets in logs
+const redacted = "[REDACTED]"
+
+// Params and result fields that hold secrets, and are never logged
+var redactedFields = map[string]bool{
+	"ssh_key":   true,
+	"auth_code": true,
+	"authcode":  true,
+	"auth-code": true,
+	"token":     true,
+	"password":  true,
+}
+
+// Headers that hold secrets, and are never logged, in canonical form
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+}
+
+// WithLogger logs every call made by the Client to the given logger: its
+// method, duration and outcome. Failed calls are logged as errors.
+// If the logger has the debug level enabled, the params, headers and result
+// of each call are logged too, with secrets like the API token, SSH keys and
+// auth codes redacted. Authorization, Proxy-Authorization, Cookie and
+// X-Api-Key headers are always redacted; sensitiveHeaders adds more, like
+// those set with WithHeader.
+func WithLogger(logger *slog.Logger, sensitiveHeaders ...string) Option {
+	return func(c *Client) error {
+		if logger == nil {
+			return errors.New("logger must not be nil")
+		}
+
+		sensitive := make(map[string]bool, len(redactedHeaders))
+		for key := range redactedHeaders {
+			sensitive[key] = true
+		}
+		for _, key := range sensitiveHeaders {
+			if !validHeaderName(key) {
+				return fmt.Errorf("invalid header name %q", key)
+			}
+			sensitive[http.CanonicalHeaderKey(key)] = true
+		}
+
+		c.middlewares = append(c.middlewares, loggingMiddleware(logger, sensitive))
+		return nil
+	}
+}
+
+func loggingMiddleware(logger *slog.Logger, sensitive map[string]bool) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (json.RawMessage, error) {
+			debug := logger.Enabled(ctx, slog.LevelDebug)
+			if debug {
+				params := slog.Any("params", redactValue(toGeneric(call.Params)))
+				if call.Batch != nil {
+					params = slog.Any("calls", redactValue(toGeneric(call.Batch)))
+				}
+
+				logger.LogAttrs(
+					ctx, slog.LevelDebug, "njalla call started",
+					slog.String("method", call.Method),
+					params,
+					slog.Any("headers", redactHeader(call.Header, sensitive)),
+				)
+			}
+
+			start := time.Now()
+			result, err := next(ctx, call)
+			duration := time.Since(start)
+
+			attrs := []slog.Attr{
+				slog.String("method", call.Method),
+				slog.Duration("duration", duration),
+			}
+			if err != nil {
+				attrs = append(
+					attrs,
+					slog.String("outcome", "error"),
+					slog.String("error", err.Error()),
+				)
+				logger.LogAttrs(ctx, slog.LevelError, "njalla call failed", attrs...)
+				return result, err
+			}
+
+			attrs = append(attrs, slog.String("outcome", "ok"))
+			if debug {
+				attrs = append(
+					attrs, slog.Any("result", redactValue(toGeneric(result))),
+				)
+			}
+			logger.LogAttrs(ctx, slog.LevelInfo, "njalla call finished", attrs...)
+
+			return result, err
+		}
+	}
+}
+
+// toGeneric converts a value to its generic JSON representation, made of
+// maps, slices and scalars, so it can be walked by redactValue.
+func toGeneric(value interface{}) interface{} {
+	data, ok := value.(json.RawMessage)
+	if !ok {
+		var err error
+		data, err = json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+	}
+
+	var generic interface{}
+	err := json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil
+	}
+
+	return generic
+}
+
+// redactValue replaces the values of secret fields in a generic JSON value
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range typed {
+			if redactedFields[key] {
+				typed[key] = redacted
+			} else {
+				typed[key] = redactValue(inner)
+			}
+		}
+	case []interface{}:
+		for i, inner := range typed {
+			typed[i] = redactValue(inner)
+		}
+	}
+
+	return value
+}
+
+// redactHeader returns a copy of the header with the values of the sensitive
+// headers replaced
+func redactHeader(
+	header http.Header, sensitive map[string]bool,
+) map[string]string {
+	result := make(map[string]string, len(header))
+	for key, values := range header {
+		if sensitive[http.CanonicalHeaderKey(key)] {
+			result[key] = redacted
+			continue
+		}
+		if len(values) > 0 {
+			result[key] = values[0]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,118 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoggedClient returns a Client logging to the returned buffer at the
+// given level, and answering every request with the given status and body
+func newLoggedClient(
+	t *testing.T, level slog.Level, status int, body string,
+) (*Client, *bytes.Buffer) {
+	var output bytes.Buffer
+	logger := slog.New(
+		slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: level}),
+	)
+
+	client, _ := newMockClient(t, func(request, int) (int, string) {
+		return status, body
+	}, WithLogger(logger))
+
+	return client, &output
+}
+
+func TestLoggerInfo(t *testing.T) {
+	client, output := newLoggedClient(
+		t, slog.LevelInfo, 200, `{"result": {"id": "1", "ssh_key": "key"}}`,
+	)
+
+	_, err := client.AddServer(
+		context.Background(), "name", "c1", "debian", "ssh-ed25519 AAAA", 1,
+	)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"method":"add-server"`)
+	assert.Contains(t, lines[0], `"outcome":"ok"`)
+	assert.Contains(t, lines[0], `"duration":`)
+	assert.NotContains(t, lines[0], "params")
+	assert.NotContains(t, lines[0], "ssh-ed25519")
+}
+
+func TestLoggerDebugRedacts(t *testing.T) {
+	client, output := newLoggedClient(
+		t, slog.LevelDebug, 200,
+		`{"result": {"id": "1", "ssh_key": "ssh-ed25519 AAAA"}}`,
+	)
+
+	_, err := client.AddServer(
+		context.Background(), "name", "c1", "debian", "ssh-ed25519 AAAA", 1,
+	)
+	assert.Nil(t, err)
+
+	logs := output.String()
+	assert.Contains(t, logs, `"params":{`)
+	assert.Contains(t, logs, `"result":{`)
+	assert.Contains(t, logs, `"name":"name"`)
+	assert.Contains(t, logs, `"ssh_key":"[REDACTED]"`)
+	assert.Contains(t, logs, `"Authorization":"[REDACTED]"`)
+	assert.NotContains(t, logs, "ssh-ed25519")
+	assert.NotContains(t, logs, "test-token")
+}
+
+func TestLoggerRedactsHeaders(t *testing.T) {
+	var output bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(
+		&output, &slog.HandlerOptions{Level: slog.LevelDebug},
+	))
+
+	client, _ := newMockClient(t, func(request, int) (int, string) {
+		return 200, `{"result": {"domains": []}}`
+	},
+		WithHeader("Proxy-Authorization", "Basic proxy-secret"),
+		WithHeader("Cookie", "session=cookie-secret"),
+		WithHeader("X-Tenant-Secret", "tenant-secret"),
+		WithHeader("X-Request-Source", "tests"),
+		WithLogger(logger, "x-tenant-secret"),
+	)
+
+	_, err := client.ListDomains(context.Background())
+	assert.Nil(t, err)
+
+	logs := output.String()
+	assert.Contains(t, logs, `"Proxy-Authorization":"[REDACTED]"`)
+	assert.Contains(t, logs, `"Cookie":"[REDACTED]"`)
+	assert.Contains(t, logs, `"X-Tenant-Secret":"[REDACTED]"`)
+	assert.Contains(t, logs, `"X-Request-Source":"tests"`)
+	assert.NotContains(t, logs, "proxy-secret")
+	assert.NotContains(t, logs, "cookie-secret")
+	assert.NotContains(t, logs, "tenant-secret")
+}
+
+func TestLoggerInvalidHeader(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	_, err := NewClient("test-token", WithLogger(logger, "bad header"))
+	assert.Error(t, err)
+}
+
+func TestLoggerError(t *testing.T) {
+	client, output := newLoggedClient(
+		t, slog.LevelInfo, 200,
+		`{"error": {"code": 404, "message": "Domain not found"}}`,
+	)
+
+	_, err := client.GetDomain(context.Background(), "testing.com")
+	assert.Error(t, err)
+
+	logs := output.String()
+	assert.Contains(t, logs, `"level":"ERROR"`)
+	assert.Contains(t, logs, `"outcome":"error"`)
+	assert.Contains(t, logs, "Domain not found")
+}
@@ -0,0 +1,58 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Call is a single call to the API, as seen by middlewares
+type Call struct {
+	// Method is the API method, like `list-records`
+	Method string
+	// Params are the parameters of the method
+	Params map[string]interface{}
+	// Batch contains the calls of a batch sent with Client.Batch, in which
+	// case Method is BatchMethod and Params is nil.
+	Batch []BatchCall
+	// Header contains the HTTP headers the call will be sent with,
+	// including `Authorization`. Middlewares may add to it, for example to
+	// sign requests.
+	Header http.Header
+}
+
+// RoundTripFunc sends a call to the API and returns its raw `result`, or an
+// error.
+type RoundTripFunc func(ctx context.Context, call *Call) (json.RawMessage, error)
+
+// Middleware wraps a RoundTripFunc to run code around every call made by a
+// Client, like logging, metrics or audits. A middleware sees the call
+// before calling `next`, and the result, error and latency after:
+//
+//	func timing(next gonjalla.RoundTripFunc) gonjalla.RoundTripFunc {
+//		return func(ctx context.Context, call *gonjalla.Call) (json.RawMessage, error) {
+//			start := time.Now()
+//			result, err := next(ctx, call)
+//			log.Printf("%s took %s: %v", call.Method, time.Since(start), err)
+//			return result, err
+//		}
+//	}
+//
+// Middlewares run once per call. Retries and rate limiting happen inside
+// `next`.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends middlewares to the Client. The first middleware
+// given is the outermost one, seeing calls first and results last.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) error {
+		for _, middleware := range middlewares {
+			if middleware == nil {
+				return errors.New("middleware must not be nil")
+			}
+		}
+		c.middlewares = append(c.middlewares, middlewares...)
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var order []string
+	var seenResult json.RawMessage
+	var seenErr error
+
+	recorder := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(
+				ctx context.Context, call *Call,
+			) (json.RawMessage, error) {
+				order = append(order, name+" "+call.Method)
+				result, err := next(ctx, call)
+				order = append(order, name+" done")
+				return result, err
+			}
+		}
+	}
+
+	signer := func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (json.RawMessage, error) {
+			assert.Equal(t, "testing.com", call.Params["domain"])
+			call.Header.Set("X-Signature", "signed")
+			result, err := next(ctx, call)
+			seenResult, seenErr = result, err
+			return result, err
+		}
+	}
+
+	var sent *http.Request
+	client, err := NewClient(
+		"test-token",
+		WithMiddleware(recorder("first"), recorder("second")),
+		WithMiddleware(signer),
+		WithHTTPClient(&mocks.MockClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				sent = req
+				testData := `{"jsonrpc": "2.0", "result": {"records": []}}`
+				return &http.Response{
+					StatusCode: 200,
+					Body: ioutil.NopCloser(
+						bytes.NewReader([]byte(testData)),
+					),
+				}, nil
+			},
+		}),
+	)
+	assert.Nil(t, err)
+
+	records, err := client.ListRecords(context.Background(), "testing.com")
+	assert.Nil(t, err)
+	assert.Equal(t, []Record{}, records)
+
+	assert.Equal(t, []string{
+		"first list-records",
+		"second list-records",
+		"second done",
+		"first done",
+	}, order)
+	assert.JSONEq(t, `{"records": []}`, string(seenResult))
+	assert.Nil(t, seenErr)
+	assert.Equal(t, "signed", sent.Header.Get("X-Signature"))
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	client, err := NewClient(
+		"test-token",
+		WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(
+				ctx context.Context, call *Call,
+			) (json.RawMessage, error) {
+				return json.RawMessage(`{"domains": []}`), nil
+			}
+		}),
+		WithHTTPClient(&mocks.MockClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				t.Error("request should not be sent")
+				return nil, nil
+			},
+		}),
+	)
+	assert.Nil(t, err)
+
+	domains, err := client.ListDomains(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []Domain{}, domains)
+}
+
+func TestWithMiddlewareNil(t *testing.T) {
+	_, err := NewClient("test-token", WithMiddleware(nil))
+	assert.Error(t, err)
+}
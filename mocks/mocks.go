@@ -12,7 +12,11 @@ var (
 	GetDoFunc func(req *http.Request) (*http.Response, error)
 )
 
-// Do is the mock client's `Do` func
+// Do is the mock client's `Do` func. It calls the client's own `DoFunc` if
+// set, and falls back to the shared `GetDoFunc` otherwise.
 func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
+	if m.DoFunc != nil {
+		return m.DoFunc(req)
+	}
 	return GetDoFunc(req)
 }
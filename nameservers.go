@@ -0,0 +1,134 @@
+package gonjalla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GetNameservers returns the custom nameservers of a domain. An empty list
+// means the domain uses Njalla's own nameservers.
+func (c *Client) GetNameservers(
+	ctx context.Context, domain string,
+) ([]string, error) {
+	domainStruct, err := c.GetDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainStruct.Nameservers, nil
+}
+
+// GetNameservers is a wrapper around Client.GetNameservers for the given
+// token.
+func GetNameservers(token string, domain string) ([]string, error) {
+	return newDefaultClient(token).GetNameservers(context.Background(), domain)
+}
+
+// SetNameservers replaces the nameservers of a domain with custom ones.
+// The hostnames are validated, and their number checked against the
+// domain's MaxNameservers before sending anything. Use ResetNameservers to
+// go back to Njalla's own nameservers.
+func (c *Client) SetNameservers(
+	ctx context.Context, domain string, nameservers []string,
+) (Domain, error) {
+	if len(nameservers) == 0 {
+		return Domain{}, errors.New(
+			"no nameservers given, use ResetNameservers to reset them",
+		)
+	}
+
+	normalized := make([]string, len(nameservers))
+	seen := map[string]bool{}
+	for i, nameserver := range nameservers {
+		hostname, err := normalizeHostname(nameserver)
+		if err != nil {
+			return Domain{}, err
+		}
+		if seen[hostname] {
+			return Domain{}, fmt.Errorf("duplicated nameserver %q", hostname)
+		}
+		seen[hostname] = true
+		normalized[i] = hostname
+	}
+
+	domainStruct, err := c.GetDomain(ctx, domain)
+	if err != nil {
+		return Domain{}, err
+	}
+
+	max := domainStruct.MaxNameservers
+	if max != nil && len(normalized) > *max {
+		return Domain{}, fmt.Errorf(
+			"%s allows at most %d nameservers, got %d",
+			domain, *max, len(normalized),
+		)
+	}
+
+	return c.EditDomain(ctx, domain, DomainSettings{Nameservers: normalized})
+}
+
+// SetNameservers is a wrapper around Client.SetNameservers for the given
+// token.
+func SetNameservers(
+	token string, domain string, nameservers []string,
+) (Domain, error) {
+	return newDefaultClient(token).SetNameservers(
+		context.Background(), domain, nameservers,
+	)
+}
+
+// ResetNameservers makes a domain use Njalla's own nameservers again
+func (c *Client) ResetNameservers(
+	ctx context.Context, domain string,
+) (Domain, error) {
+	return c.EditDomain(ctx, domain, DomainSettings{Nameservers: []string{}})
+}
+
+// ResetNameservers is a wrapper around Client.ResetNameservers for the given
+// token.
+func ResetNameservers(token string, domain string) (Domain, error) {
+	return newDefaultClient(token).ResetNameservers(context.Background(), domain)
+}
+
+// normalizeHostname validates a hostname as per RFC 1123, and returns it in
+// lowercase and without a trailing dot.
+func normalizeHostname(hostname string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSuffix(hostname, "."))
+	if normalized == "" || len(normalized) > 253 {
+		return "", fmt.Errorf("invalid hostname %q", hostname)
+	}
+
+	labels := strings.Split(normalized, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("hostname %q is not fully qualified", hostname)
+	}
+
+	for _, label := range labels {
+		if !validLabel(label) {
+			return "", fmt.Errorf("invalid hostname %q", hostname)
+		}
+	}
+
+	return normalized, nil
+}
+
+// validLabel reports whether label is a valid lowercase DNS label: up to 63
+// letters, digits and hyphens, not starting or ending with a hyphen.
+func validLabel(label string) bool {
+	if label == "" || len(label) > 63 {
+		return false
+	}
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for _, r := range label {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+
+	return true
+}
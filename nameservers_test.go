@@ -0,0 +1,123 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestGetNameservers(t *testing.T) {
+	client, _ := newResponsesClient(t, `{
+		"jsonrpc": "2.0",
+		"result": {
+			"name": "testing.com",
+			"status": "active",
+			"expiry": "2021-02-20T19:38:48Z",
+			"nameservers": ["ns1.testing.com", "ns2.testing.com"],
+			"max_nameservers": 2
+		}
+	}`)
+
+	nameservers, err := client.GetNameservers(
+		context.Background(), "testing.com",
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ns1.testing.com", "ns2.testing.com"}, nameservers)
+}
+
+func TestSetNameservers(t *testing.T) {
+	client, sent := newResponsesClient(
+		t,
+		`{"result": {"name": "testing.com", "max_nameservers": 2}}`,
+		`{"result": {
+			"name": "testing.com",
+			"nameservers": ["ns1.example.net", "ns2.example.net"]
+		}}`,
+	)
+
+	domain, err := client.SetNameservers(
+		context.Background(), "testing.com",
+		[]string{"NS1.example.net.", "ns2.example.net"},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"get-domain", "edit-domain"}, methodsOf(*sent))
+	assert.Equal(
+		t, []string{"ns1.example.net", "ns2.example.net"}, domain.Nameservers,
+	)
+}
+
+func TestSetNameserversTooMany(t *testing.T) {
+	client, sent := newResponsesClient(
+		t, `{"result": {"name": "testing.com", "max_nameservers": 1}}`,
+	)
+
+	_, err := client.SetNameservers(
+		context.Background(), "testing.com",
+		[]string{"ns1.example.net", "ns2.example.net"},
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"get-domain"}, methodsOf(*sent))
+}
+
+func TestSetNameserversInvalid(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"result": {}}`)
+
+	invalid := [][]string{
+		{},
+		{"ns1"},
+		{"-ns1.example.net"},
+		{"ns_1.example.net"},
+		{"ns1..example.net"},
+		{"ns1.example.net", "NS1.example.net"},
+	}
+
+	for _, nameservers := range invalid {
+		_, err := client.SetNameservers(
+			context.Background(), "testing.com", nameservers,
+		)
+		assert.Error(t, err, nameservers)
+	}
+	assert.Empty(t, *sent)
+}
+
+func TestResetNameservers(t *testing.T) {
+	client, sent := newResponsesClient(
+		t, `{"result": {"name": "testing.com"}}`,
+	)
+
+	_, err := client.ResetNameservers(context.Background(), "testing.com")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"edit-domain"}, methodsOf(*sent))
+}
+
+func TestGetNameserversWrapper(t *testing.T) {
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"name": "testing.com",
+			"status": "active",
+			"expiry": "2021-02-20T19:38:48Z",
+			"nameservers": ["ns1.testing.com"]
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	nameservers, err := GetNameservers("test-token", "testing.com")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ns1.testing.com"}, nameservers)
+}
@@ -2,6 +2,7 @@ package gonjalla
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,22 +14,29 @@ type request struct {
 	Params map[string]interface{} `json:"params"`
 }
 
+// response is the JSON-RPC envelope returned by the API. Only one of
+// `Result` or `Error` is expected to be set.
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
 const endpoint string = "https://njal.la/api/1/"
 
+// userAgent is sent in the User-Agent header of every request
+const userAgent string = "gonjalla (+https://github.com/Sighery/gonjalla)"
+
 // HTTPClient interface. Useful for mocked unit tests later on.
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
 var (
-	// Client used in all requests by Request. Can be overwritten for tests.
-	Client HTTPClient
+	// DefaultHTTPClient is used by the package-level functions, and by any
+	// Client created without WithHTTPClient. Can be overwritten for tests.
+	DefaultHTTPClient HTTPClient = &http.Client{}
 )
 
-func init() {
-	Client = &http.Client{}
-}
-
 // Request common function for all of Njalla's API.
 // Njalla's API uses JSON-RPC, and contains just one endpoint.
 // The endpoint is POST only, and takes in a JSON in the body, with two
@@ -36,25 +44,102 @@ func init() {
 // The `params` argument is variable. Some methods require no parameters,
 // (like `list-domains`), while other methods require parameters (like
 // `get-domain` which requires `domain: string`).
-func Request(
-	token string, method string, params map[string]interface{},
+// The context is attached to the underlying HTTP request, so cancelling it
+// aborts the call.
+// The call goes through the middlewares set with WithMiddleware, if any.
+func (c *Client) Request(
+	ctx context.Context, method string, params map[string]interface{},
 ) ([]byte, error) {
-	token = fmt.Sprintf("Njalla %s", token)
+	return c.do(ctx, c.newCall(method, params))
+}
 
-	body, err := json.Marshal(
-		request{Method: method, Params: params},
-	)
+// newCall returns a Call with the headers every request is sent with
+func (c *Client) newCall(method string, params map[string]interface{}) *Call {
+	header := c.headers.Clone()
+	header.Set("Authorization", fmt.Sprintf("Njalla %s", c.token))
+	header.Set("Content-Type", "application/json")
+	header.Set("User-Agent", c.userAgent)
+
+	return &Call{Method: method, Params: params, Header: header}
+}
+
+// do sends a call through the middleware chain
+func (c *Client) do(ctx context.Context, call *Call) (json.RawMessage, error) {
+	roundTrip := c.roundTrip
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		roundTrip = c.middlewares[i](roundTrip)
+	}
+
+	return roundTrip(ctx, call)
+}
+
+// roundTrip sends a call to the API, applying rate limits and retries. It's
+// the innermost RoundTripFunc of the middleware chain.
+func (c *Client) roundTrip(
+	ctx context.Context, call *Call,
+) (json.RawMessage, error) {
+	body, err := encodeCall(call)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(body))
+	attempts := c.retryPolicy.attemptsFor(call)
+	for attempt := 1; ; attempt++ {
+		err = c.wait(ctx, call.Method)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := c.send(ctx, call, body)
+		if err == nil {
+			return result, nil
+		}
+
+		if attempt >= attempts || !shouldRetry(ctx, err) {
+			return nil, err
+		}
+
+		err = sleepContext(ctx, c.retryPolicy.delay(attempt, err))
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// encodeCall returns the JSON body of the HTTP request for a call
+func encodeCall(call *Call) ([]byte, error) {
+	if call.Batch == nil {
+		return json.Marshal(request{Method: call.Method, Params: call.Params})
+	}
+
+	requests := make([]batchRequest, len(call.Batch))
+	for i, batchCall := range call.Batch {
+		requests[i] = batchRequest{
+			JSONRPC: "2.0",
+			ID:      i + 1,
+			Method:  batchCall.Method,
+			Params:  batchCall.Params,
+		}
+	}
+
+	return json.Marshal(requests)
+}
+
+// send makes a single HTTP request to the API, and unwraps its response.
+func (c *Client) send(
+	ctx context.Context, call *Call, body []byte,
+) (json.RawMessage, error) {
+	method := call.Method
+
+	req, err := http.NewRequestWithContext(
+		ctx, "POST", c.baseURL, bytes.NewBuffer(body),
+	)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Authorization", token)
+	req.Header = call.Header.Clone()
 
-	resp, err := Client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -65,22 +150,47 @@ func Request(
 		return nil, err
 	}
 
-	data := make(map[string]interface{})
+	if call.Batch != nil {
+		return decodeBatch(resp, jsonData)
+	}
 
+	var data response
 	err = json.Unmarshal(jsonData, &data)
 	if err != nil {
-		return nil, err
+		return nil, newHTTPError(method, resp, jsonData, err)
 	}
 
-	result, ok := data["result"]
-	if !ok {
-		return nil, fmt.Errorf("Missing result %s", data)
+	var apiErr error
+	if data.Error != nil && string(data.Error) != "null" {
+		apiErr = newAPIError(method, data.Error)
 	}
 
-	unwrapped, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newHTTPError(method, resp, jsonData, apiErr)
+	}
+
+	if apiErr != nil {
+		return nil, apiErr
 	}
 
-	return unwrapped, nil
+	if data.Result == nil {
+		return nil, fmt.Errorf("Missing result %s", jsonData)
+	}
+
+	return data.Result, nil
+}
+
+// Request is a wrapper around Client.Request for the given token.
+func Request(
+	token string, method string, params map[string]interface{},
+) ([]byte, error) {
+	return RequestContext(context.Background(), token, method, params)
+}
+
+// RequestContext is a wrapper around Client.Request for the given token.
+func RequestContext(
+	ctx context.Context, token string, method string,
+	params map[string]interface{},
+) ([]byte, error) {
+	return newDefaultClient(token).Request(ctx, method, params)
 }
@@ -0,0 +1,126 @@
+package gonjalla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is waited on by a Client before every request it sends to the API.
+// RateLimiter implements it, and so does `golang.org/x/time/rate.Limiter`.
+type Limiter interface {
+	// Wait blocks until the request is allowed to go through, or returns an
+	// error if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
+// RateLimiter is a token bucket Limiter. It allows bursts of up to `burst`
+// requests, refilled at `rate` requests per second. It's safe to share a
+// RateLimiter between goroutines and between Clients.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a RateLimiter allowing `rate` requests per second,
+// with bursts of up to `burst` requests. It starts with a full bucket.
+func NewRateLimiter(rate float64, burst int) (*RateLimiter, error) {
+	if !(rate > 0) || math.IsInf(rate, 1) {
+		return nil, fmt.Errorf("rate must be positive, got %v", rate)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// Wait takes a token from the bucket, waiting for one to be refilled if it's
+// empty. If ctx is done before then, the token is given back and the
+// context's error is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	wait := clampDuration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	err = sleepContext(ctx, wait)
+	if err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// WithRateLimiter sets a Limiter waited on before every request sent by the
+// Client, including retries.
+func WithRateLimiter(limiter Limiter) Option {
+	return func(c *Client) error {
+		if limiter == nil {
+			return errors.New("rate limiter must not be nil")
+		}
+		c.limiter = limiter
+		return nil
+	}
+}
+
+// WithMethodRateLimiter sets a Limiter for requests to one API method, like
+// `find-domains`. It's used instead of the one set by WithRateLimiter for
+// that method.
+func WithMethodRateLimiter(method string, limiter Limiter) Option {
+	return func(c *Client) error {
+		if method == "" {
+			return errors.New("rate limiter method must not be empty")
+		}
+		if limiter == nil {
+			return errors.New("rate limiter must not be nil")
+		}
+		if c.methodLimiters == nil {
+			c.methodLimiters = map[string]Limiter{}
+		}
+		c.methodLimiters[method] = limiter
+		return nil
+	}
+}
+
+// wait blocks on the Limiter that applies to the given method, if any
+func (c *Client) wait(ctx context.Context, method string) error {
+	limiter, ok := c.methodLimiters[method]
+	if !ok {
+		limiter = c.limiter
+	}
+	if limiter == nil {
+		return nil
+	}
+
+	return limiter.Wait(ctx)
+}
@@ -0,0 +1,134 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+type countingLimiter struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	return nil
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter, err := NewRateLimiter(1000, 5)
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, limiter.Wait(ctx))
+	}
+	assert.Less(t, time.Since(start), 5*time.Millisecond)
+
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, limiter.Wait(ctx))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 8*time.Millisecond)
+}
+
+func TestNewRateLimiterInvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		limiter, err := NewRateLimiter(rate, 1)
+		assert.Nil(t, limiter)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestRateLimiterContextCancelled(t *testing.T) {
+	limiter, err := NewRateLimiter(0.001, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 10*time.Millisecond,
+	)
+	defer cancel()
+
+	err = limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRateLimiterTinyRate(t *testing.T) {
+	limiter, err := NewRateLimiter(1e-10, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 10*time.Millisecond,
+	)
+	defer cancel()
+
+	err = limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	limiter, err := NewRateLimiter(500, 1)
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(start), 16*time.Millisecond)
+}
+
+func TestClientMethodRateLimiter(t *testing.T) {
+	global := &countingLimiter{}
+	find := &countingLimiter{}
+
+	client, err := NewClient(
+		"test-token",
+		WithRateLimiter(global),
+		WithMethodRateLimiter("find-domains", find),
+		WithHTTPClient(&mocks.MockClient{
+			DoFunc: func(*http.Request) (*http.Response, error) {
+				testData := `{"result": {"domains": []}}`
+				return &http.Response{
+					StatusCode: 200,
+					Body: ioutil.NopCloser(
+						bytes.NewReader([]byte(testData)),
+					),
+				}, nil
+			},
+		}),
+	)
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	_, err = client.ListDomains(ctx)
+	assert.Nil(t, err)
+	_, err = client.ListDomains(ctx)
+	assert.Nil(t, err)
+	_, err = client.FindDomains(ctx, "testing")
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, global.calls)
+	assert.Equal(t, 1, find.calls)
+}
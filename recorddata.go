@@ -0,0 +1,746 @@
+package gonjalla
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RecordData is the typed content of a DNS record, like MXRecord. Each type
+// knows which fields of the raw Record it's stored in: an MX record keeps its
+// host in Content and its priority in Priority, an SRV record also uses
+// Weight and Port, and so on.
+type RecordData interface {
+	// Type returns the DNS record type, like "MX"
+	Type() string
+	// Validate checks the fields of the record
+	Validate() error
+
+	// toRecord stores the data in the fields of a raw Record
+	toRecord(record *Record)
+}
+
+// NewRecord returns the raw Record for the given name, TTL and data, ready to
+// be passed to Client.AddRecord
+func NewRecord(name string, ttl int, data RecordData) (Record, error) {
+	if data == nil {
+		return Record{}, errors.New("record data must not be nil")
+	}
+	if !slices.Contains(ValidTTL, ttl) {
+		return Record{}, fmt.Errorf("invalid TTL %d, must be one of %v", ttl, ValidTTL)
+	}
+
+	err := data.Validate()
+	if err != nil {
+		return Record{}, err
+	}
+
+	record := Record{Name: name, Type: data.Type(), TTL: ttl}
+	data.toRecord(&record)
+
+	return record, nil
+}
+
+// Data parses the record into the RecordData struct of its type, like
+// *MXRecord for MX records. Returns an error for record types without one,
+// or if the record is malformed or doesn't pass validation.
+func (r Record) Data() (RecordData, error) {
+	var data RecordData
+	var err error
+
+	switch strings.ToUpper(r.Type) {
+	case "A":
+		data, err = parseARecord(r)
+	case "AAAA":
+		data, err = parseAAAARecord(r)
+	case "CNAME":
+		data = &CNAMERecord{Target: r.Content}
+	case "MX":
+		data, err = parseMXRecord(r)
+	case "TXT":
+		data = &TXTRecord{Text: r.Content}
+	case "SRV":
+		data, err = parseSRVRecord(r)
+	case "CAA":
+		data, err = parseCAARecord(r)
+	case "NS":
+		data = &NSRecord{Host: r.Content}
+	case "PTR":
+		data = &PTRRecord{Host: r.Content}
+	case "SSHFP":
+		data, err = parseSSHFPRecord(r)
+	case "TLSA":
+		data, err = parseTLSARecord(r)
+	case "HTTPS":
+		var svcb SVCBRecord
+		svcb, err = parseSVCBRecord(r)
+		https := HTTPSRecord(svcb)
+		data = &https
+	case "SVCB":
+		var svcb SVCBRecord
+		svcb, err = parseSVCBRecord(r)
+		data = &svcb
+	case "NAPTR":
+		data, err = parseNAPTRRecord(r)
+	case "DS":
+		data, err = parseDSRecord(r)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", r.Type)
+	}
+	if err == nil {
+		err = data.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s record %q: %w", r.Type, r.Content, err)
+	}
+
+	return data, nil
+}
+
+// ARecord maps a name to an IPv4 address
+type ARecord struct {
+	Address netip.Addr
+}
+
+// Type returns "A"
+func (d ARecord) Type() string { return "A" }
+
+// Validate checks that the address is an IPv4 address
+func (d ARecord) Validate() error {
+	if !d.Address.Is4() {
+		return fmt.Errorf("A record address %q is not IPv4", d.Address)
+	}
+	return nil
+}
+
+func (d ARecord) toRecord(record *Record) {
+	record.Content = d.Address.String()
+}
+
+func parseARecord(r Record) (*ARecord, error) {
+	address, err := netip.ParseAddr(r.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &ARecord{Address: address}, nil
+}
+
+// AAAARecord maps a name to an IPv6 address
+type AAAARecord struct {
+	Address netip.Addr
+}
+
+// Type returns "AAAA"
+func (d AAAARecord) Type() string { return "AAAA" }
+
+// Validate checks that the address is an IPv6 address without a zone
+func (d AAAARecord) Validate() error {
+	if !d.Address.Is6() || d.Address.Is4In6() || d.Address.Zone() != "" {
+		return fmt.Errorf("AAAA record address %q is not IPv6", d.Address)
+	}
+	return nil
+}
+
+func (d AAAARecord) toRecord(record *Record) {
+	record.Content = d.Address.String()
+}
+
+func parseAAAARecord(r Record) (*AAAARecord, error) {
+	address, err := netip.ParseAddr(r.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &AAAARecord{Address: address}, nil
+}
+
+// CNAMERecord makes a name an alias of another
+type CNAMERecord struct {
+	Target string
+}
+
+// Type returns "CNAME"
+func (d CNAMERecord) Type() string { return "CNAME" }
+
+// Validate checks that the target is a name
+func (d CNAMERecord) Validate() error {
+	return validateTarget("CNAME", d.Target)
+}
+
+func (d CNAMERecord) toRecord(record *Record) {
+	record.Content = d.Target
+}
+
+// MXRecord points to a mail server of the domain
+type MXRecord struct {
+	Priority int
+	Host     string
+}
+
+// Type returns "MX"
+func (d MXRecord) Type() string { return "MX" }
+
+// Validate checks the priority against ValidPriority, and that the host is
+// a name
+func (d MXRecord) Validate() error {
+	err := validatePriority("MX", d.Priority)
+	if err != nil {
+		return err
+	}
+	return validateTarget("MX", d.Host)
+}
+
+func (d MXRecord) toRecord(record *Record) {
+	record.Content = d.Host
+	record.Priority = intPtr(d.Priority)
+}
+
+func parseMXRecord(r Record) (*MXRecord, error) {
+	if r.Priority == nil {
+		return nil, errors.New("missing priority")
+	}
+	return &MXRecord{Priority: *r.Priority, Host: r.Content}, nil
+}
+
+// TXTRecord holds arbitrary text, like SPF policies or domain verifications
+type TXTRecord struct {
+	Text string
+}
+
+// Type returns "TXT"
+func (d TXTRecord) Type() string { return "TXT" }
+
+// Validate checks that the text isn't empty
+func (d TXTRecord) Validate() error {
+	if d.Text == "" {
+		return errors.New("TXT record text must not be empty")
+	}
+	return nil
+}
+
+func (d TXTRecord) toRecord(record *Record) {
+	record.Content = d.Text
+}
+
+// SRVRecord points to the host and port of a service, as per RFC 2782
+type SRVRecord struct {
+	Priority int
+	Weight   int
+	Port     int
+	Target   string
+}
+
+// Type returns "SRV"
+func (d SRVRecord) Type() string { return "SRV" }
+
+// Validate checks the priority against ValidPriority, that the weight and
+// port fit in 16 bits, and that the target is a name
+func (d SRVRecord) Validate() error {
+	err := validatePriority("SRV", d.Priority)
+	if err != nil {
+		return err
+	}
+	if d.Weight < 0 || d.Weight > 65535 {
+		return fmt.Errorf("SRV record weight %d out of range", d.Weight)
+	}
+	if d.Port < 0 || d.Port > 65535 {
+		return fmt.Errorf("SRV record port %d out of range", d.Port)
+	}
+	return validateTarget("SRV", d.Target)
+}
+
+func (d SRVRecord) toRecord(record *Record) {
+	record.Content = d.Target
+	record.Priority = intPtr(d.Priority)
+	record.Weight = intPtr(d.Weight)
+	record.Port = intPtr(d.Port)
+}
+
+func parseSRVRecord(r Record) (*SRVRecord, error) {
+	if r.Priority == nil || r.Weight == nil || r.Port == nil {
+		return nil, errors.New("missing priority, weight or port")
+	}
+	return &SRVRecord{
+		Priority: *r.Priority,
+		Weight:   *r.Weight,
+		Port:     *r.Port,
+		Target:   r.Content,
+	}, nil
+}
+
+// CAARecord restricts which certificate authorities may issue certificates
+// for the domain, as per RFC 8659
+type CAARecord struct {
+	Flags uint8
+	// Tag is the property, like "issue", "issuewild" or "iodef"
+	Tag   string
+	Value string
+}
+
+// Type returns "CAA"
+func (d CAARecord) Type() string { return "CAA" }
+
+// Validate checks that the tag is made of up to 15 letters and digits
+func (d CAARecord) Validate() error {
+	if d.Tag == "" || len(d.Tag) > 15 {
+		return fmt.Errorf("invalid CAA record tag %q", d.Tag)
+	}
+	for _, r := range d.Tag {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("invalid CAA record tag %q", d.Tag)
+		}
+	}
+	return nil
+}
+
+func (d CAARecord) toRecord(record *Record) {
+	record.Content = fmt.Sprintf("%d %s %s", d.Flags, d.Tag, quoteField(d.Value))
+}
+
+func parseCAARecord(r Record) (*CAARecord, error) {
+	fields, err := splitFields(r.Content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 3 {
+		return nil, errors.New("expected flags, tag and value")
+	}
+
+	flags, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flags %q", fields[0])
+	}
+
+	return &CAARecord{Flags: uint8(flags), Tag: fields[1], Value: fields[2]}, nil
+}
+
+// NSRecord delegates a subdomain to a name server
+type NSRecord struct {
+	Host string
+}
+
+// Type returns "NS"
+func (d NSRecord) Type() string { return "NS" }
+
+// Validate checks that the host is a name
+func (d NSRecord) Validate() error {
+	return validateTarget("NS", d.Host)
+}
+
+func (d NSRecord) toRecord(record *Record) {
+	record.Content = d.Host
+}
+
+// PTRRecord points to a name, usually for reverse DNS
+type PTRRecord struct {
+	Host string
+}
+
+// Type returns "PTR"
+func (d PTRRecord) Type() string { return "PTR" }
+
+// Validate checks that the host is a name
+func (d PTRRecord) Validate() error {
+	return validateTarget("PTR", d.Host)
+}
+
+func (d PTRRecord) toRecord(record *Record) {
+	record.Content = d.Host
+}
+
+// SSHFPAlgorithms maps the SSHFP algorithm numbers to their mnemonic, as per
+// the IANA "SSHFP RR Types for public key algorithms" registry
+var SSHFPAlgorithms = map[uint8]string{
+	1: "RSA",
+	2: "DSA",
+	3: "ECDSA",
+	4: "Ed25519",
+	6: "Ed448",
+}
+
+// Length in bytes of the fingerprints of each SSHFP fingerprint type
+var sshfpFingerprintLengths = map[uint8]int{
+	1: 20,
+	2: 32,
+}
+
+// SSHFPRecord publishes the fingerprint of a host's SSH key, as per RFC 4255
+type SSHFPRecord struct {
+	Algorithm uint8
+	// FingerprintType is 1 for SHA-1 and 2 for SHA-256
+	FingerprintType uint8
+	// Fingerprint is the hex encoded fingerprint
+	Fingerprint string
+}
+
+// Type returns "SSHFP"
+func (d SSHFPRecord) Type() string { return "SSHFP" }
+
+// Validate checks the algorithm and fingerprint type against the IANA
+// registries, and that the fingerprint is hex of the right length for its
+// type
+func (d SSHFPRecord) Validate() error {
+	if _, ok := SSHFPAlgorithms[d.Algorithm]; !ok {
+		return fmt.Errorf("unknown SSHFP algorithm %d", d.Algorithm)
+	}
+
+	length, ok := sshfpFingerprintLengths[d.FingerprintType]
+	if !ok {
+		return fmt.Errorf("unknown SSHFP fingerprint type %d", d.FingerprintType)
+	}
+
+	return validateHex("SSHFP fingerprint", d.Fingerprint, length)
+}
+
+func (d SSHFPRecord) toRecord(record *Record) {
+	record.Content = d.Fingerprint
+	record.SSHAlgorithm = intPtr(int(d.Algorithm))
+	record.SSHType = intPtr(int(d.FingerprintType))
+}
+
+func parseSSHFPRecord(r Record) (*SSHFPRecord, error) {
+	if r.SSHAlgorithm == nil || r.SSHType == nil {
+		return nil, errors.New("missing SSH algorithm or type")
+	}
+	if *r.SSHAlgorithm < 0 || *r.SSHAlgorithm > 255 ||
+		*r.SSHType < 0 || *r.SSHType > 255 {
+		return nil, errors.New("SSH algorithm or type out of range")
+	}
+
+	return &SSHFPRecord{
+		Algorithm:       uint8(*r.SSHAlgorithm),
+		FingerprintType: uint8(*r.SSHType),
+		Fingerprint:     r.Content,
+	}, nil
+}
+
+// Length in bytes of the data of each TLSA matching type, other than 0 (the
+// full certificate or key)
+var tlsaDataLengths = map[uint8]int{
+	1: 32,
+	2: 64,
+}
+
+// TLSARecord associates a TLS certificate or key with a service, as per
+// RFC 6698
+type TLSARecord struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	// Data is the hex encoded certificate association data
+	Data string
+}
+
+// Type returns "TLSA"
+func (d TLSARecord) Type() string { return "TLSA" }
+
+// Validate checks the usage, selector and matching type, and that the data
+// is hex of the right length for the matching type
+func (d TLSARecord) Validate() error {
+	if d.Usage > 3 {
+		return fmt.Errorf("unknown TLSA usage %d", d.Usage)
+	}
+	if d.Selector > 1 {
+		return fmt.Errorf("unknown TLSA selector %d", d.Selector)
+	}
+	if d.MatchingType > 2 {
+		return fmt.Errorf("unknown TLSA matching type %d", d.MatchingType)
+	}
+
+	return validateHex("TLSA data", d.Data, tlsaDataLengths[d.MatchingType])
+}
+
+func (d TLSARecord) toRecord(record *Record) {
+	record.Content = fmt.Sprintf(
+		"%d %d %d %s", d.Usage, d.Selector, d.MatchingType, d.Data,
+	)
+}
+
+func parseTLSARecord(r Record) (*TLSARecord, error) {
+	fields := strings.Fields(r.Content)
+	if len(fields) != 4 {
+		return nil, errors.New("expected usage, selector, matching type and data")
+	}
+
+	numbers, err := parseUint8s(fields[:3])
+	if err != nil {
+		return nil, err
+	}
+
+	return &TLSARecord{
+		Usage:        numbers[0],
+		Selector:     numbers[1],
+		MatchingType: numbers[2],
+		Data:         fields[3],
+	}, nil
+}
+
+// SVCBRecord points to the endpoints of a service, as per RFC 9460. A
+// priority of 0 makes it an alias of Target.
+type SVCBRecord struct {
+	Priority int
+	Target   string
+	// Params are the service parameters in presentation format, like
+	// `alpn=h2,h3 port=8443`
+	Params string
+}
+
+// Type returns "SVCB"
+func (d SVCBRecord) Type() string { return "SVCB" }
+
+// Validate checks that the priority fits in 16 bits, that alias records have
+// no params, and that the target is a name
+func (d SVCBRecord) Validate() error {
+	return d.validate("SVCB")
+}
+
+func (d SVCBRecord) validate(recordType string) error {
+	// Unlike MX and SRV, any 16-bit priority is used to order endpoints
+	if d.Priority < 0 || d.Priority > 65535 {
+		return fmt.Errorf(
+			"%s record priority %d out of range", recordType, d.Priority,
+		)
+	}
+	if d.Priority == 0 && d.Params != "" {
+		return fmt.Errorf("%s alias records must not have params", recordType)
+	}
+	return validateTarget(recordType, d.Target)
+}
+
+func (d SVCBRecord) toRecord(record *Record) {
+	record.Content = d.Target
+	if d.Params != "" {
+		record.Content += " " + d.Params
+	}
+	record.Priority = intPtr(d.Priority)
+}
+
+func parseSVCBRecord(r Record) (SVCBRecord, error) {
+	if r.Priority == nil {
+		return SVCBRecord{}, errors.New("missing priority")
+	}
+
+	target, params, _ := strings.Cut(strings.TrimSpace(r.Content), " ")
+	return SVCBRecord{
+		Priority: *r.Priority,
+		Target:   target,
+		Params:   strings.TrimSpace(params),
+	}, nil
+}
+
+// HTTPSRecord is an SVCBRecord for HTTPS services, as per RFC 9460
+type HTTPSRecord SVCBRecord
+
+// Type returns "HTTPS"
+func (d HTTPSRecord) Type() string { return "HTTPS" }
+
+// Validate checks that the priority fits in 16 bits, that alias records have
+// no params, and that the target is a name
+func (d HTTPSRecord) Validate() error {
+	return SVCBRecord(d).validate("HTTPS")
+}
+
+func (d HTTPSRecord) toRecord(record *Record) {
+	SVCBRecord(d).toRecord(record)
+}
+
+// NAPTRRecord rewrites a name into a URI or another name, as per RFC 3403
+type NAPTRRecord struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+// Type returns "NAPTR"
+func (d NAPTRRecord) Type() string { return "NAPTR" }
+
+// Validate checks that only one of the regexp and replacement is set, as
+// the other must be empty or "." respectively
+func (d NAPTRRecord) Validate() error {
+	hasReplacement := d.Replacement != "" && d.Replacement != "."
+	if d.Regexp != "" && hasReplacement {
+		return errors.New("NAPTR record must not have both a regexp and a replacement")
+	}
+	return nil
+}
+
+func (d NAPTRRecord) toRecord(record *Record) {
+	replacement := d.Replacement
+	if replacement == "" {
+		replacement = "."
+	}
+
+	record.Content = fmt.Sprintf(
+		"%d %d %s %s %s %s", d.Order, d.Preference, quoteField(d.Flags),
+		quoteField(d.Service), quoteField(d.Regexp), replacement,
+	)
+}
+
+func parseNAPTRRecord(r Record) (*NAPTRRecord, error) {
+	fields, err := splitFields(r.Content)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 6 {
+		return nil, errors.New(
+			"expected order, preference, flags, service, regexp and replacement",
+		)
+	}
+
+	order, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid order %q", fields[0])
+	}
+	preference, err := strconv.ParseUint(fields[1], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid preference %q", fields[1])
+	}
+
+	return &NAPTRRecord{
+		Order:       uint16(order),
+		Preference:  uint16(preference),
+		Flags:       fields[2],
+		Service:     fields[3],
+		Regexp:      fields[4],
+		Replacement: fields[5],
+	}, nil
+}
+
+// Type returns "DS". DSRecord is also used as RecordData, for DS records
+// added to the zone of a domain, like when delegating a signed subdomain.
+// Its ID is ignored there.
+func (d DSRecord) Type() string { return "DS" }
+
+func (d DSRecord) toRecord(record *Record) {
+	record.Content = fmt.Sprintf(
+		"%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest,
+	)
+}
+
+func parseDSRecord(r Record) (*DSRecord, error) {
+	fields := strings.Fields(r.Content)
+	if len(fields) < 4 {
+		return nil, errors.New("expected key tag, algorithm, digest type and digest")
+	}
+
+	keyTag, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key tag %q", fields[0])
+	}
+	numbers, err := parseUint8s(fields[1:3])
+	if err != nil {
+		return nil, err
+	}
+
+	return &DSRecord{
+		KeyTag:     uint16(keyTag),
+		Algorithm:  numbers[0],
+		DigestType: numbers[1],
+		Digest:     strings.Join(fields[3:], ""),
+	}, nil
+}
+
+// validatePriority checks a priority against ValidPriority
+func validatePriority(recordType string, priority int) error {
+	if !slices.Contains(ValidPriority, priority) {
+		return fmt.Errorf(
+			"invalid %s record priority %d, must be one of %v",
+			recordType, priority, ValidPriority,
+		)
+	}
+	return nil
+}
+
+// validateTarget checks that a target looks like a domain name. Relative
+// names, like "mail", are accepted too.
+func validateTarget(recordType string, target string) error {
+	if target == "" || len(target) > 253 || strings.ContainsAny(target, " \t\n") {
+		return fmt.Errorf("invalid %s record target %q", recordType, target)
+	}
+	return nil
+}
+
+// validateHex checks that value is hex encoded, and `length` bytes long if
+// length isn't 0
+func validateHex(what string, value string, length int) error {
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("%s is not hex: %w", what, err)
+	}
+	if len(decoded) == 0 {
+		return fmt.Errorf("%s must not be empty", what)
+	}
+	if length != 0 && len(decoded) != length {
+		return fmt.Errorf(
+			"%s must be %d bytes long, got %d", what, length, len(decoded),
+		)
+	}
+	return nil
+}
+
+// parseUint8s parses each of the fields as an 8-bit number
+func parseUint8s(fields []string) ([]uint8, error) {
+	numbers := make([]uint8, len(fields))
+	for i, field := range fields {
+		number, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", field)
+		}
+		numbers[i] = uint8(number)
+	}
+	return numbers, nil
+}
+
+// quoteField returns value as a DNS character string, in double quotes
+func quoteField(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + value + `"`
+}
+
+// splitFields splits record content on whitespace, keeping double quoted
+// character strings, which may contain spaces and `\"` escapes, as one field
+func splitFields(content string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted, escaped := false, false, false
+
+	for _, r := range content {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			inField = true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if quoted || escaped {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+
+	return fields, nil
+}
+
+func intPtr(value int) *int {
+	return &value
+}
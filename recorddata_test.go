@@ -0,0 +1,169 @@
+package gonjalla
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDataRoundTrip(t *testing.T) {
+	tests := []struct {
+		data    RecordData
+		content string
+	}{
+		{&ARecord{Address: netip.MustParseAddr("1.2.3.4")}, "1.2.3.4"},
+		{&AAAARecord{Address: netip.MustParseAddr("2001:db8::1")}, "2001:db8::1"},
+		{&CNAMERecord{Target: "example.com."}, "example.com."},
+		{&MXRecord{Priority: 10, Host: "mail.protonmail.ch"}, "mail.protonmail.ch"},
+		{&TXTRecord{Text: "v=spf1 -all"}, "v=spf1 -all"},
+		{
+			&SRVRecord{Priority: 10, Weight: 5, Port: 5060, Target: "sip.example.com"},
+			"sip.example.com",
+		},
+		{
+			&CAARecord{Tag: "issue", Value: `letsencrypt.org; "x"`},
+			`0 issue "letsencrypt.org; \"x\""`,
+		},
+		{&NSRecord{Host: "ns1.example.com"}, "ns1.example.com"},
+		{&PTRRecord{Host: "host.example.com"}, "host.example.com"},
+		{
+			&SSHFPRecord{
+				Algorithm:       4,
+				FingerprintType: 2,
+				Fingerprint:     "123456789abcdef67890123456789abcdef67890123456789abcdef123456789",
+			},
+			"123456789abcdef67890123456789abcdef67890123456789abcdef123456789",
+		},
+		{
+			&TLSARecord{
+				Usage:        3,
+				Selector:     1,
+				MatchingType: 1,
+				Data:         "0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6",
+			},
+			"3 1 1 0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6",
+		},
+		{
+			&HTTPSRecord{Priority: 1, Target: ".", Params: "alpn=h2,h3"},
+			". alpn=h2,h3",
+		},
+		{
+			&HTTPSRecord{Priority: 2, Target: "cdn.example.com", Params: "alpn=h2"},
+			"cdn.example.com alpn=h2",
+		},
+		{&SVCBRecord{Priority: 0, Target: "svc.example.com"}, "svc.example.com"},
+		{
+			&NAPTRRecord{
+				Order:       100,
+				Preference:  10,
+				Flags:       "S",
+				Service:     "SIP+D2U",
+				Replacement: "_sip._udp.example.com.",
+			},
+			`100 10 "S" "SIP+D2U" "" _sip._udp.example.com.`,
+		},
+		{
+			&DSRecord{
+				KeyTag:     60485,
+				Algorithm:  5,
+				DigestType: 1,
+				Digest:     "2BB183AF5F22588179A53B0A98631FAD1A292118",
+			},
+			"60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118",
+		},
+	}
+
+	for _, test := range tests {
+		record, err := NewRecord("@", 3600, test.data)
+		assert.Nil(t, err, test.data.Type())
+		assert.Equal(t, test.data.Type(), record.Type)
+		assert.Equal(t, test.content, record.Content, test.data.Type())
+
+		data, err := record.Data()
+		assert.Nil(t, err, test.data.Type())
+		assert.Equal(t, test.data, data)
+	}
+}
+
+func TestRecordDataWireFields(t *testing.T) {
+	record, err := NewRecord("_sip._udp", 300, SRVRecord{
+		Priority: 20, Weight: 5, Port: 5060, Target: "sip.example.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 20, *record.Priority)
+	assert.Equal(t, 5, *record.Weight)
+	assert.Equal(t, 5060, *record.Port)
+	assert.Nil(t, record.SSHAlgorithm)
+
+	record, err = NewRecord("@", 300, SSHFPRecord{
+		Algorithm:       1,
+		FingerprintType: 1,
+		Fingerprint:     "dd465c09cfa51fb45020cc83316fff21b9ec74ac",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, *record.SSHAlgorithm)
+	assert.Equal(t, 1, *record.SSHType)
+	assert.Nil(t, record.Priority)
+}
+
+func TestRecordDataInvalid(t *testing.T) {
+	invalid := []RecordData{
+		ARecord{Address: netip.MustParseAddr("2001:db8::1")},
+		AAAARecord{Address: netip.MustParseAddr("::ffff:1.2.3.4")},
+		AAAARecord{Address: netip.MustParseAddr("fe80::1%eth0")},
+		CNAMERecord{},
+		MXRecord{Priority: 7, Host: "mail.example.com"},
+		TXTRecord{},
+		SRVRecord{Priority: 10, Port: 70000, Target: "sip.example.com"},
+		CAARecord{Tag: "is-sue"},
+		SSHFPRecord{Algorithm: 5, FingerprintType: 1, Fingerprint: "00"},
+		SSHFPRecord{Algorithm: 1, FingerprintType: 2, Fingerprint: "00"},
+		TLSARecord{Usage: 4, Data: "00"},
+		SVCBRecord{Priority: 0, Target: ".", Params: "alpn=h2"},
+		SVCBRecord{Priority: 70000, Target: "."},
+		NAPTRRecord{Regexp: "!^.*$!sip:info@example.com!", Replacement: "example.com"},
+		DSRecord{KeyTag: 1, Algorithm: 13, DigestType: 2, Digest: "00"},
+	}
+	for _, data := range invalid {
+		_, err := NewRecord("@", 3600, data)
+		assert.NotNil(t, err, "%#v", data)
+	}
+
+	_, err := NewRecord("@", 42, TXTRecord{Text: "text"})
+	assert.NotNil(t, err)
+}
+
+func TestRecordDataParseErrors(t *testing.T) {
+	invalid := []Record{
+		{Type: "A", Content: "not-an-ip"},
+		{Type: "MX", Content: "mail.example.com"},
+		{Type: "MX", Content: "", Priority: intPtr(7)},
+		{Type: "SRV", Content: "sip.example.com"},
+		{
+			Type:     "SRV",
+			Content:  "sip.example.com",
+			Priority: intPtr(10),
+			Weight:   intPtr(5),
+			Port:     intPtr(70000),
+		},
+		{Type: "CNAME", Content: ""},
+		{Type: "TXT", Content: ""},
+		{Type: "HTTPS", Content: ". alpn=h2", Priority: intPtr(0)},
+		{Type: "CAA", Content: `0 issue "unterminated`},
+		{Type: "SSHFP", Content: "00"},
+		{Type: "TLSA", Content: "3 1"},
+		{Type: "NAPTR", Content: `100 10 "S"`},
+		{Type: "LOC", Content: "52 22 23.000 N 4 53 32.000 E -2.00m"},
+	}
+	for _, record := range invalid {
+		_, err := record.Data()
+		assert.NotNil(t, err, "%#v", record)
+	}
+}
+
+func TestSplitFields(t *testing.T) {
+	fields, err := splitFields(`0  issue "a \"quoted\" value" ""`)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"0", "issue", `a "quoted" value`, ""}, fields)
+}
@@ -1,6 +1,9 @@
 package gonjalla
 
-import "encoding/json"
+import (
+	"context"
+	"encoding/json"
+)
 
 // ValidTTL is an array containing all the valid TTL values
 var ValidTTL = []int{60, 300, 900, 3600, 10800, 21600, 86400}
@@ -8,22 +11,28 @@ var ValidTTL = []int{60, 300, 900, 3600, 10800, 21600, 86400}
 // ValidPriority is an array containing all the valid Priority values
 var ValidPriority = []int{0, 1, 5, 10, 20, 30, 40, 50, 60}
 
-// Record struct contains data returned by `list-records`
+// Record struct contains data returned by `list-records`. It's the raw form
+// of a record, as sent to the API; NewRecord and Record.Data convert it from
+// and to the typed RecordData structs.
 type Record struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	Content  string `json:"content"`
-	TTL      int    `json:"ttl"`
-	Priority *int   `json:"prio,omitempty"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Content      string `json:"content"`
+	TTL          int    `json:"ttl"`
+	Priority     *int   `json:"prio,omitempty"`
+	Weight       *int   `json:"weight,omitempty"`
+	Port         *int   `json:"port,omitempty"`
+	SSHAlgorithm *int   `json:"ssh_algorithm,omitempty"`
+	SSHType      *int   `json:"ssh_type,omitempty"`
 }
 
 // ListRecords returns a listing of all records for a given domain
-func ListRecords(token string, domain string) ([]Record, error) {
+func (c *Client) ListRecords(ctx context.Context, domain string) ([]Record, error) {
 	params := map[string]interface{}{
 		"domain": domain,
 	}
-	data, err := Request(token, "list-records", params)
+	data, err := c.Request(ctx, "list-records", params)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +50,16 @@ func ListRecords(token string, domain string) ([]Record, error) {
 	return response.Records, nil
 }
 
+// ListRecords is a wrapper around Client.ListRecords for the given token.
+func ListRecords(token string, domain string) ([]Record, error) {
+	return newDefaultClient(token).ListRecords(context.Background(), domain)
+}
+
 // AddRecord adds a given record to a given domain.
 // Returns a new Record struct, containing the response from the API if
 // successful. This response will have some fields like ID (which can only
 // be known after the execution) filled.
-func AddRecord(token string, domain string, record Record) (Record, error) {
+func (c *Client) AddRecord(ctx context.Context, domain string, record Record) (Record, error) {
 	marshal, err := json.Marshal(record)
 	if err != nil {
 		return Record{}, err
@@ -59,7 +73,7 @@ func AddRecord(token string, domain string, record Record) (Record, error) {
 		return Record{}, err
 	}
 
-	data, err := Request(token, "add-record", params)
+	data, err := c.Request(ctx, "add-record", params)
 	if err != nil {
 		return Record{}, err
 	}
@@ -73,15 +87,20 @@ func AddRecord(token string, domain string, record Record) (Record, error) {
 	return response, nil
 }
 
+// AddRecord is a wrapper around Client.AddRecord for the given token.
+func AddRecord(token string, domain string, record Record) (Record, error) {
+	return newDefaultClient(token).AddRecord(context.Background(), domain, record)
+}
+
 // RemoveRecord removes a given record from a given domain.
 // If there are no errors it will return `nil`.
-func RemoveRecord(token string, domain string, id string) error {
+func (c *Client) RemoveRecord(ctx context.Context, domain string, id string) error {
 	params := map[string]interface{}{
 		"domain": domain,
 		"id":     id,
 	}
 
-	_, err := Request(token, "remove-record", params)
+	_, err := c.Request(ctx, "remove-record", params)
 	if err != nil {
 		return err
 	}
@@ -89,6 +108,11 @@ func RemoveRecord(token string, domain string, id string) error {
 	return nil
 }
 
+// RemoveRecord is a wrapper around Client.RemoveRecord for the given token.
+func RemoveRecord(token string, domain string, id string) error {
+	return newDefaultClient(token).RemoveRecord(context.Background(), domain, id)
+}
+
 // EditRecord edits a record for a given domain.
 // This function is fairly dumb. It takes in a `Record` struct, and uses all
 // its filled fields to send to Njalla.
@@ -98,7 +122,7 @@ func RemoveRecord(token string, domain string, id string) error {
 // Note that the record type cannot be changed, so if you want to do so, you'll
 // have to remove and create the record again under a different type. Trying to
 // change the record type will just return an API error.
-func EditRecord(token string, domain string, record Record) error {
+func (c *Client) EditRecord(ctx context.Context, domain string, record Record) error {
 	marshal, err := json.Marshal(record)
 	if err != nil {
 		return err
@@ -112,10 +136,15 @@ func EditRecord(token string, domain string, record Record) error {
 		return err
 	}
 
-	_, err = Request(token, "edit-record", params)
+	_, err = c.Request(ctx, "edit-record", params)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// EditRecord is a wrapper around Client.EditRecord for the given token.
+func EditRecord(token string, domain string, record Record) error {
+	return newDefaultClient(token).EditRecord(context.Background(), domain, record)
+}
@@ -14,7 +14,7 @@ import (
 func TestListRecordsExpected(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -106,7 +106,7 @@ func TestListRecordsExpected(t *testing.T) {
 func TestListRecordsError(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -135,7 +135,7 @@ func TestListRecordsError(t *testing.T) {
 func TestAddRecordExpected(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -187,7 +187,7 @@ func TestAddRecordExpected(t *testing.T) {
 func TestAddRecordError(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -222,7 +222,7 @@ func TestRemoveRecordExpected(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
 	id := "1337"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -245,7 +245,7 @@ func TestRemoveRecordError(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
 	id := "1337"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -270,7 +270,7 @@ func TestRemoveRecordError(t *testing.T) {
 func TestEditRecordExpected(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -309,7 +309,7 @@ func TestEditRecordExpected(t *testing.T) {
 func TestEditRecordError(t *testing.T) {
 	token := "test-token"
 	domain := "testing.com"
-	Client = &mocks.MockClient{}
+	DefaultHTTPClient = &mocks.MockClient{}
 
 	testData := `{
 		"jsonrpc": "2.0",
@@ -0,0 +1,220 @@
+package gonjalla
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries calls that failed because of
+// transient network errors, server errors (5xx) or rate limiting (429).
+// API errors (like a missing domain) are never retried.
+//
+// By default only read-only methods, which are safe to send more than once,
+// are retried. Set RetryMutating to retry every method.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts, after jitter, including
+	// delays from Backoff or asked for by a `Retry-After` header
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after each attempt. Defaults to 2.
+	Multiplier float64
+	// Jitter randomises each delay by up to this fraction of it, between 0
+	// and 1, so concurrent clients don't retry in lockstep.
+	Jitter float64
+	// Backoff, if set, replaces the exponential curve above. It's given the
+	// number of the attempt that just failed, starting at 1.
+	Backoff func(attempt int) time.Duration
+	// RetryMutating enables retries for methods that change state, like
+	// `add-record`. Njalla may have applied the first attempt even if its
+	// response got lost, so only enable this if duplicates are acceptable.
+	RetryMutating bool
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and exponential
+// backoff starting at half a second.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the Client. Clients don't retry
+// at all by default.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) error {
+		if policy.MaxAttempts < 0 {
+			return errors.New("retry policy max attempts must not be negative")
+		}
+		if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+			return errors.New("retry policy backoffs must not be negative")
+		}
+		if policy.Multiplier != 0 && policy.Multiplier < 1 {
+			return errors.New("retry policy multiplier must be at least 1")
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return errors.New("retry policy jitter must be between 0 and 1")
+		}
+		c.retryPolicy = &policy
+		return nil
+	}
+}
+
+// Methods that only read data, and can be safely sent more than once
+var idempotentMethods = map[string]bool{
+	"list-domains":       true,
+	"get-domain":         true,
+	"get-auth-code":      true,
+	"find-domains":       true,
+	"check-task":         true,
+	"list-records":       true,
+	"list-forwards":      true,
+	"list-glue":          true,
+	"list-dnssec":        true,
+	"list-tlds":          true,
+	"get-tld":            true,
+	"list-servers":       true,
+	"list-server-images": true,
+	"list-server-types":  true,
+}
+
+// attempts returns how many times the given method may be sent
+func (p *RetryPolicy) attempts(method string) int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+	if !p.RetryMutating && !idempotentMethods[method] {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// attemptsFor returns how many times the given call may be sent. A batch
+// call is only retried if all of its methods may be.
+func (p *RetryPolicy) attemptsFor(call *Call) int {
+	if call.Batch == nil {
+		return p.attempts(call.Method)
+	}
+
+	if p == nil || len(call.Batch) == 0 {
+		return 1
+	}
+	for _, batchCall := range call.Batch {
+		if p.attempts(batchCall.Method) == 1 {
+			return 1
+		}
+	}
+
+	return p.MaxAttempts
+}
+
+// delay returns how long to wait after the given failed attempt. A
+// `Retry-After` header sent by the API takes precedence, capped to
+// MaxBackoff if it's set.
+func (p *RetryPolicy) delay(attempt int, err error) time.Duration {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		retryAfter := parseRetryAfter(httpErr.Header.Get("Retry-After"))
+		if retryAfter > 0 {
+			if p.MaxBackoff > 0 && retryAfter > p.MaxBackoff {
+				return p.MaxBackoff
+			}
+			return retryAfter
+		}
+	}
+
+	var delay float64
+	if p.Backoff != nil {
+		delay = float64(p.Backoff(attempt))
+	} else {
+		multiplier := p.Multiplier
+		if multiplier == 0 {
+			multiplier = 2
+		}
+		// Computed as a float, as large attempts overflow a Duration, and
+		// kept finite so jitter can't turn it into NaN
+		delay = float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+		delay = math.Min(delay, math.MaxInt64)
+	}
+
+	if p.Jitter > 0 {
+		delay += (rand.Float64()*2 - 1) * p.Jitter * delay
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	return clampDuration(delay)
+}
+
+// clampDuration converts a float number of nanoseconds to a Duration,
+// between 0 and the longest Duration
+func clampDuration(nanoseconds float64) time.Duration {
+	if nanoseconds <= 0 || math.IsNaN(nanoseconds) {
+		return 0
+	}
+	if nanoseconds >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(nanoseconds)
+}
+
+// shouldRetry reports whether a failed attempt is worth sending again
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if IsRetryable(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses the value of a `Retry-After` header, which is either
+// a number of seconds or an HTTP date. Returns 0 if it can't be parsed.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(value)
+	if err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+// sleepContext waits for the given duration, or until ctx is done
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package gonjalla
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSequenceClient returns a Client whose responses are taken in order from
+// the given status codes and bodies, and a pointer to the requests sent.
+func newSequenceClient(
+	t *testing.T, policy RetryPolicy, statuses []int, bodies []string,
+) (*Client, *[]request) {
+	return newMockClient(t, func(_ request, n int) (int, string) {
+		return statuses[n], bodies[n]
+	}, WithRetryPolicy(policy))
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestRetryIdempotentMethod(t *testing.T) {
+	client, sent := newSequenceClient(
+		t, testRetryPolicy(),
+		[]int{502, 503, 200},
+		[]string{"Bad Gateway", "Unavailable", `{"result": {"domains": []}}`},
+	)
+
+	domains, err := client.ListDomains(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []Domain{}, domains)
+	assert.Len(t, *sent, 3)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	client, sent := newSequenceClient(
+		t, testRetryPolicy(),
+		[]int{502, 502, 502},
+		[]string{"Bad Gateway", "Bad Gateway", "Bad Gateway"},
+	)
+
+	_, err := client.ListDomains(context.Background())
+	assert.True(t, IsRetryable(err))
+	assert.Len(t, *sent, 3)
+}
+
+func TestRetrySkipsMutatingMethod(t *testing.T) {
+	client, sent := newSequenceClient(
+		t, testRetryPolicy(),
+		[]int{502, 200},
+		[]string{"Bad Gateway", `{"result": {}}`},
+	)
+
+	err := client.RemoveRecord(context.Background(), "testing.com", "1337")
+	assert.Error(t, err)
+	assert.Len(t, *sent, 1)
+}
+
+func TestRetryMutatingOptIn(t *testing.T) {
+	policy := testRetryPolicy()
+	policy.RetryMutating = true
+	client, sent := newSequenceClient(
+		t, policy,
+		[]int{502, 200},
+		[]string{"Bad Gateway", `{"result": {}}`},
+	)
+
+	err := client.RemoveRecord(context.Background(), "testing.com", "1337")
+	assert.Nil(t, err)
+	assert.Len(t, *sent, 2)
+}
+
+func TestRetrySkipsAPIErrors(t *testing.T) {
+	client, sent := newSequenceClient(
+		t, testRetryPolicy(),
+		[]int{200, 200},
+		[]string{
+			`{"error": {"code": 404, "message": "Not found"}}`,
+			`{"result": {}}`,
+		},
+	)
+
+	_, err := client.GetDomain(context.Background(), "testing.com")
+	assert.True(t, IsNotFound(err))
+	assert.Len(t, *sent, 1)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+	}
+
+	assert.Equal(t, time.Second, policy.delay(1, nil))
+	assert.Equal(t, 2*time.Second, policy.delay(2, nil))
+	assert.Equal(t, 4*time.Second, policy.delay(3, nil))
+	assert.Equal(t, 5*time.Second, policy.delay(4, nil))
+
+	rateLimited := &HTTPError{
+		StatusCode: 429,
+		Header:     http.Header{"Retry-After": {"30"}},
+	}
+	assert.Equal(t, 5*time.Second, policy.delay(1, rateLimited))
+	rateLimited.Header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, policy.delay(1, rateLimited))
+
+	for _, attempt := range []int{40, 60, 70, 2000} {
+		assert.Equal(t, 5*time.Second, policy.delay(attempt, nil))
+	}
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, policy.delay(10, nil), 5*time.Second)
+		assert.GreaterOrEqual(t, policy.delay(2000, nil), 2500*time.Millisecond)
+	}
+	policy.Jitter = 0
+
+	policy.Backoff = func(int) time.Duration { return time.Hour }
+	assert.Equal(t, 5*time.Second, policy.delay(1, nil))
+	policy.Backoff = nil
+
+	policy.MaxBackoff = 0
+	assert.Equal(t, time.Duration(math.MaxInt64), policy.delay(70, nil))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.delay(1, nil)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+}
+
+func TestWithRetryPolicyValidation(t *testing.T) {
+	_, err := NewClient("test-token", WithRetryPolicy(RetryPolicy{Jitter: 2}))
+	assert.Error(t, err)
+
+	_, err = NewClient(
+		"test-token", WithRetryPolicy(RetryPolicy{Multiplier: 0.5}),
+	)
+	assert.Error(t, err)
+}
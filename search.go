@@ -0,0 +1,168 @@
+package gonjalla
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default number of concurrent `find-domains` calls made by SearchDomains
+const defaultSearchConcurrency = 4
+
+// SearchOptions are the parameters of SearchDomains
+type SearchOptions struct {
+	// Labels are the candidate names to look up, like `example`. Each one
+	// is sent as its own `find-domains` query.
+	Labels []string
+	// TLDs, if set, only keeps results of these TLDs, like `com` or `net`
+	TLDs []string
+	// Statuses, if set, only keeps results with one of these statuses
+	Statuses []MarketStatus
+	// Concurrency is the number of queries run at once. Defaults to 4.
+	// Queries still wait on the Client's rate limiter, if any.
+	Concurrency int
+}
+
+// SearchResults are the domains found by SearchDomains
+type SearchResults []MarketDomain
+
+// SortByPrice sorts the results by ascending price, then by name
+func (r SearchResults) SortByPrice() {
+	sort.SliceStable(r, func(i, j int) bool {
+		if r[i].Price != r[j].Price {
+			return r[i].Price < r[j].Price
+		}
+		return r[i].Name < r[j].Name
+	})
+}
+
+// Filter returns the results with one of the given statuses
+func (r SearchResults) Filter(statuses ...MarketStatus) SearchResults {
+	wanted := map[MarketStatus]bool{}
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	filtered := SearchResults{}
+	for _, domain := range r {
+		if wanted[domain.Status] {
+			filtered = append(filtered, domain)
+		}
+	}
+
+	return filtered
+}
+
+// SearchDomains runs `find-domains` for many candidate labels concurrently,
+// and merges the results. Duplicated labels and results are removed, and
+// results are filtered by the TLDs and statuses in opts. Results are sorted
+// by name; use SortByPrice to rank them by price instead.
+// If any query fails, the remaining ones are cancelled and the error is
+// returned.
+func (c *Client) SearchDomains(
+	ctx context.Context, opts SearchOptions,
+) (SearchResults, error) {
+	labels := uniqueLowercase(opts.Labels)
+	if len(labels) == 0 {
+		return nil, errors.New("no labels to search for")
+	}
+
+	tlds := map[string]bool{}
+	for _, tld := range uniqueLowercase(opts.TLDs) {
+		tlds[strings.TrimPrefix(tld, ".")] = true
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultSearchConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		found    = map[string]MarketDomain{}
+	)
+
+	queue := make(chan string)
+	for i := 0; i < concurrency && i < len(labels); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for label := range queue {
+				domains, err := c.FindDomains(ctx, label)
+
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+				} else {
+					for _, domain := range domains {
+						found[strings.ToLower(domain.Name)] = domain
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+enqueue:
+	for _, label := range labels {
+		select {
+		case queue <- label:
+		case <-ctx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	results := SearchResults{}
+	for name, domain := range found {
+		if len(tlds) > 0 && !tlds[name[strings.IndexByte(name, '.')+1:]] {
+			continue
+		}
+		results = append(results, domain)
+	}
+	if len(opts.Statuses) > 0 {
+		results = results.Filter(opts.Statuses...)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results, nil
+}
+
+// uniqueLowercase returns the non-empty values in lowercase, without
+// duplicates, in their original order
+func uniqueLowercase(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+
+	return unique
+}
@@ -0,0 +1,105 @@
+package gonjalla
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSearchClient returns a Client answering `find-domains` queries with the
+// given bodies per query, and a pointer to the requests sent
+func newSearchClient(
+	t *testing.T, bodies map[string]string,
+) (*Client, *[]request) {
+	return newMockClient(t, func(sent request, _ int) (int, string) {
+		query := sent.Params["query"].(string)
+		testData, ok := bodies[query]
+		if !ok {
+			testData = fmt.Sprintf(
+				`{"error": {"code": 0, "message": "No %s"}}`, query,
+			)
+		}
+		return 200, testData
+	})
+}
+
+func TestSearchDomainsExpected(t *testing.T) {
+	client, sent := newSearchClient(t, map[string]string{
+		"testing": `{"result": {"domains": [
+			{"name": "testing.com", "status": "taken", "price": 45},
+			{"name": "testing.net", "status": "available", "price": 30},
+			{"name": "testing.rocks", "status": "in progress", "price": 15}
+		]}}`,
+		"testing.net": `{"result": {"domains": [
+			{"name": "testing.net", "status": "available", "price": 30}
+		]}}`,
+		"example": `{"result": {"domains": [
+			{"name": "example.com", "status": "available", "price": 15},
+			{"name": "example.net", "status": "failed", "price": 30}
+		]}}`,
+	})
+
+	results, err := client.SearchDomains(context.Background(), SearchOptions{
+		Labels:      []string{"testing", "Testing", "testing.net", "example"},
+		TLDs:        []string{".com", "net"},
+		Concurrency: 2,
+	})
+	assert.Nil(t, err)
+	var queries []string
+	for _, single := range *sent {
+		queries = append(queries, single.Params["query"].(string))
+	}
+	assert.ElementsMatch(
+		t, []string{"testing", "testing.net", "example"}, queries,
+	)
+
+	assert.Equal(t, SearchResults{
+		{Name: "example.com", Status: MarketAvailable, Price: 15},
+		{Name: "example.net", Status: MarketFailed, Price: 30},
+		{Name: "testing.com", Status: MarketTaken, Price: 45},
+		{Name: "testing.net", Status: MarketAvailable, Price: 30},
+	}, results)
+
+	results = results.Filter(MarketAvailable, MarketTaken)
+	results.SortByPrice()
+	assert.Equal(t, SearchResults{
+		{Name: "example.com", Status: MarketAvailable, Price: 15},
+		{Name: "testing.net", Status: MarketAvailable, Price: 30},
+		{Name: "testing.com", Status: MarketTaken, Price: 45},
+	}, results)
+}
+
+func TestSearchDomainsStatuses(t *testing.T) {
+	client, _ := newSearchClient(t, map[string]string{
+		"testing": `{"result": {"domains": [
+			{"name": "testing.com", "status": "taken", "price": 45},
+			{"name": "testing.net", "status": "available", "price": 30}
+		]}}`,
+	})
+
+	results, err := client.SearchDomains(context.Background(), SearchOptions{
+		Labels:   []string{"testing"},
+		Statuses: []MarketStatus{MarketAvailable},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, SearchResults{
+		{Name: "testing.net", Status: MarketAvailable, Price: 30},
+	}, results)
+}
+
+func TestSearchDomainsError(t *testing.T) {
+	client, _ := newSearchClient(t, map[string]string{
+		"testing": `{"result": {"domains": []}}`,
+	})
+
+	results, err := client.SearchDomains(context.Background(), SearchOptions{
+		Labels: []string{"testing", "missing"},
+	})
+	assert.Nil(t, results)
+	assert.Error(t, err)
+
+	_, err = client.SearchDomains(context.Background(), SearchOptions{})
+	assert.Error(t, err)
+}
@@ -1,29 +1,30 @@
 package gonjalla
 
 import (
+	"context"
 	"encoding/json"
 )
 
 // Server struct contains data returned by api calls that deal with server state
 type Server struct {
-	Name        string   `json:"name"`
-	Type        string   `json:"type"`
-	ID          string   `json:"id"`
-	Status      string   `json:"status"`
-	Os          string   `json:"os"`
-	Expiry      string   `json:"expiry"`
-	Autorenew   bool     `json:"autorenew"`
-	SSHKey      string   `json:"ssh_key"`
-	Ips         []string `json:"ips"`
-	ReverseName string   `json:"reverse_name"`
-	OsState     string   `json:"os_state"`
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	ID          string        `json:"id"`
+	Status      ServerStatus  `json:"status"`
+	Os          string        `json:"os"`
+	Expiry      string        `json:"expiry"`
+	Autorenew   bool          `json:"autorenew"`
+	SSHKey      string        `json:"ssh_key"`
+	Ips         []string      `json:"ips"`
+	ReverseName string        `json:"reverse_name"`
+	OsState     ServerOSState `json:"os_state"`
 }
 
 // ListServers returns a listing of all servers for a given account
-func ListServers(token string) ([]Server, error) {
+func (c *Client) ListServers(ctx context.Context) ([]Server, error) {
 	params := map[string]interface{}{}
 
-	data, err := Request(token, "list-servers", params)
+	data, err := c.Request(ctx, "list-servers", params)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +42,16 @@ func ListServers(token string) ([]Server, error) {
 	return response.Servers, nil
 }
 
+// ListServers is a wrapper around Client.ListServers for the given token.
+func ListServers(token string) ([]Server, error) {
+	return newDefaultClient(token).ListServers(context.Background())
+}
+
 // ListServerImages returns a listing of the avaliable server images
-func ListServerImages(token string) ([]string, error) {
+func (c *Client) ListServerImages(ctx context.Context) ([]string, error) {
 	params := map[string]interface{}{}
 
-	data, err := Request(token, "list-server-images", params)
+	data, err := c.Request(ctx, "list-server-images", params)
 	if err != nil {
 		return nil, err
 	}
@@ -63,11 +69,16 @@ func ListServerImages(token string) ([]string, error) {
 	return response.Images, nil
 }
 
+// ListServerImages is a wrapper around Client.ListServerImages for the given token.
+func ListServerImages(token string) ([]string, error) {
+	return newDefaultClient(token).ListServerImages(context.Background())
+}
+
 // ListServerTypes returns a listing of the avaliable server types
-func ListServerTypes(token string) ([]string, error) {
+func (c *Client) ListServerTypes(ctx context.Context) ([]string, error) {
 	params := map[string]interface{}{}
 
-	data, err := Request(token, "list-server-types", params)
+	data, err := c.Request(ctx, "list-server-types", params)
 	if err != nil {
 		return nil, err
 	}
@@ -85,15 +96,20 @@ func ListServerTypes(token string) ([]string, error) {
 	return response.Types, nil
 }
 
+// ListServerTypes is a wrapper around Client.ListServerTypes for the given token.
+func ListServerTypes(token string) ([]string, error) {
+	return newDefaultClient(token).ListServerTypes(context.Background())
+}
+
 // StopServer stops a server from running.  Server data will not be destroyed.
-func StopServer(token string, id string) (Server, error) {
+func (c *Client) StopServer(ctx context.Context, id string) (Server, error) {
 	params := map[string]interface{}{
 		"id": id,
 	}
 
 	var server Server
 
-	data, err := Request(token, "stop-server", params)
+	data, err := c.Request(ctx, "stop-server", params)
 	if err != nil {
 		return server, err
 	}
@@ -106,15 +122,20 @@ func StopServer(token string, id string) (Server, error) {
 	return server, nil
 }
 
+// StopServer is a wrapper around Client.StopServer for the given token.
+func StopServer(token string, id string) (Server, error) {
+	return newDefaultClient(token).StopServer(context.Background(), id)
+}
+
 // StartServer starts a server
-func StartServer(token string, id string) (Server, error) {
+func (c *Client) StartServer(ctx context.Context, id string) (Server, error) {
 	params := map[string]interface{}{
 		"id": id,
 	}
 
 	var server Server
 
-	data, err := Request(token, "start-server", params)
+	data, err := c.Request(ctx, "start-server", params)
 	if err != nil {
 		return server, err
 	}
@@ -127,15 +148,20 @@ func StartServer(token string, id string) (Server, error) {
 	return server, nil
 }
 
+// StartServer is a wrapper around Client.StartServer for the given token.
+func StartServer(token string, id string) (Server, error) {
+	return newDefaultClient(token).StartServer(context.Background(), id)
+}
+
 // RestartServer restarts a server
-func RestartServer(token string, id string) (Server, error) {
+func (c *Client) RestartServer(ctx context.Context, id string) (Server, error) {
 	params := map[string]interface{}{
 		"id": id,
 	}
 
 	var server Server
 
-	data, err := Request(token, "restart-server", params)
+	data, err := c.Request(ctx, "restart-server", params)
 	if err != nil {
 		return server, err
 	}
@@ -148,8 +174,13 @@ func RestartServer(token string, id string) (Server, error) {
 	return server, nil
 }
 
+// RestartServer is a wrapper around Client.RestartServer for the given token.
+func RestartServer(token string, id string) (Server, error) {
+	return newDefaultClient(token).RestartServer(context.Background(), id)
+}
+
 // ResetServer resets a server with new server settings.  Server data WILL be destroyed
-func ResetServer(token string, id string, os string, publicKey string, instanceType string) (Server, error) {
+func (c *Client) ResetServer(ctx context.Context, id string, os string, publicKey string, instanceType string) (Server, error) {
 	params := map[string]interface{}{
 		"id":      id,
 		"os":      os,
@@ -159,7 +190,7 @@ func ResetServer(token string, id string, os string, publicKey string, instanceT
 
 	var server Server
 
-	data, err := Request(token, "reset-server", params)
+	data, err := c.Request(ctx, "reset-server", params)
 	if err != nil {
 		return server, err
 	}
@@ -172,8 +203,13 @@ func ResetServer(token string, id string, os string, publicKey string, instanceT
 	return server, nil
 }
 
+// ResetServer is a wrapper around Client.ResetServer for the given token.
+func ResetServer(token string, id string, os string, publicKey string, instanceType string) (Server, error) {
+	return newDefaultClient(token).ResetServer(context.Background(), id, os, publicKey, instanceType)
+}
+
 // AddServer creates a new server.
-func AddServer(token string, name string, instanceType string, os string, publicKey string, months int) (Server, error) {
+func (c *Client) AddServer(ctx context.Context, name string, instanceType string, os string, publicKey string, months int) (Server, error) {
 	params := map[string]interface{}{
 		"name":    name,
 		"type":    instanceType,
@@ -184,7 +220,7 @@ func AddServer(token string, name string, instanceType string, os string, public
 
 	var server Server
 
-	data, err := Request(token, "add-server", params)
+	data, err := c.Request(ctx, "add-server", params)
 	if err != nil {
 		return server, err
 	}
@@ -197,15 +233,20 @@ func AddServer(token string, name string, instanceType string, os string, public
 	return server, nil
 }
 
+// AddServer is a wrapper around Client.AddServer for the given token.
+func AddServer(token string, name string, instanceType string, os string, publicKey string, months int) (Server, error) {
+	return newDefaultClient(token).AddServer(context.Background(), name, instanceType, os, publicKey, months)
+}
+
 // RemoveServer removes a server. Server data WILL be destroyed.
-func RemoveServer(token string, id string) (Server, error) {
+func (c *Client) RemoveServer(ctx context.Context, id string) (Server, error) {
 	params := map[string]interface{}{
 		"id": id,
 	}
 
 	var server Server
 
-	data, err := Request(token, "remove-server", params)
+	data, err := c.Request(ctx, "remove-server", params)
 	if err != nil {
 		return server, err
 	}
@@ -217,3 +258,8 @@ func RemoveServer(token string, id string) (Server, error) {
 
 	return server, nil
 }
+
+// RemoveServer is a wrapper around Client.RemoveServer for the given token.
+func RemoveServer(token string, id string) (Server, error) {
+	return newDefaultClient(token).RemoveServer(context.Background(), id)
+}
@@ -0,0 +1,187 @@
+package gonjalla
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// The status types below are plain strings, so values unknown to this
+// library are kept as they are instead of failing to unmarshal. IsKnown
+// tells them apart from the constants.
+//
+// IsActive reports whether the status is the normal, working one, and
+// IsTerminal whether it's settled, as opposed to a transitional status that
+// will change by itself. Unknown statuses are neither.
+
+// DomainStatus is the status of a domain in the account
+type DomainStatus string
+
+// Domain statuses returned by `list-domains` and `get-domain`
+const (
+	DomainActive       DomainStatus = "active"
+	DomainInactive     DomainStatus = "inactive"
+	DomainPending      DomainStatus = "pending"
+	DomainTransferring DomainStatus = "transferring"
+	DomainExpired      DomainStatus = "expired"
+	DomainDeleted      DomainStatus = "deleted"
+)
+
+// IsKnown reports whether the status is one of the DomainStatus constants
+func (s DomainStatus) IsKnown() bool {
+	switch s {
+	case DomainActive, DomainInactive, DomainPending, DomainTransferring,
+		DomainExpired, DomainDeleted:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether the domain is registered and working
+func (s DomainStatus) IsActive() bool {
+	return s == DomainActive
+}
+
+// IsTerminal reports whether the domain isn't pending a registration or
+// transfer
+func (s DomainStatus) IsTerminal() bool {
+	return s.IsKnown() && s != DomainPending && s != DomainTransferring
+}
+
+// UnmarshalJSON accepts any JSON value, keeping unknown statuses as they are
+func (s *DomainStatus) UnmarshalJSON(data []byte) error {
+	*s = DomainStatus(unmarshalStatus(data))
+	return nil
+}
+
+// MarketStatus is the availability of a domain returned by `find-domains`
+type MarketStatus string
+
+// Availabilities returned by `find-domains`
+const (
+	MarketAvailable  MarketStatus = "available"
+	MarketTaken      MarketStatus = "taken"
+	MarketInProgress MarketStatus = "in progress"
+	MarketFailed     MarketStatus = "failed"
+)
+
+// IsKnown reports whether the status is one of the MarketStatus constants
+func (s MarketStatus) IsKnown() bool {
+	switch s {
+	case MarketAvailable, MarketTaken, MarketInProgress, MarketFailed:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether the domain can be registered
+func (s MarketStatus) IsActive() bool {
+	return s == MarketAvailable
+}
+
+// IsTerminal reports whether the availability check is done
+func (s MarketStatus) IsTerminal() bool {
+	return s.IsKnown() && s != MarketInProgress
+}
+
+// UnmarshalJSON accepts any JSON value, keeping unknown statuses as they are
+func (s *MarketStatus) UnmarshalJSON(data []byte) error {
+	*s = MarketStatus(unmarshalStatus(data))
+	return nil
+}
+
+// ServerStatus is the power status of a server
+type ServerStatus string
+
+// Server statuses returned by the server methods
+const (
+	ServerRunning    ServerStatus = "running"
+	ServerStopped    ServerStatus = "stopped"
+	ServerStarting   ServerStatus = "starting"
+	ServerStopping   ServerStatus = "stopping"
+	ServerRestarting ServerStatus = "restarting"
+	ServerResetting  ServerStatus = "resetting"
+	ServerCreating   ServerStatus = "creating"
+	ServerRemoving   ServerStatus = "removing"
+	ServerRemoved    ServerStatus = "removed"
+)
+
+// IsKnown reports whether the status is one of the ServerStatus constants
+func (s ServerStatus) IsKnown() bool {
+	switch s {
+	case ServerRunning, ServerStopped, ServerStarting, ServerStopping,
+		ServerRestarting, ServerResetting, ServerCreating, ServerRemoving,
+		ServerRemoved:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether the server is running
+func (s ServerStatus) IsActive() bool {
+	return s == ServerRunning
+}
+
+// IsTerminal reports whether the server is done starting, stopping, etc.
+func (s ServerStatus) IsTerminal() bool {
+	return s == ServerRunning || s == ServerStopped || s == ServerRemoved
+}
+
+// UnmarshalJSON accepts any JSON value, keeping unknown statuses as they are
+func (s *ServerStatus) UnmarshalJSON(data []byte) error {
+	*s = ServerStatus(unmarshalStatus(data))
+	return nil
+}
+
+// ServerOSState is the state of a server's operating system installation
+type ServerOSState string
+
+// Operating system states returned by the server methods
+const (
+	ServerOSInstalled    ServerOSState = "installed"
+	ServerOSInstalling   ServerOSState = "installing"
+	ServerOSReinstalling ServerOSState = "reinstalling"
+	ServerOSFailed       ServerOSState = "failed"
+)
+
+// IsKnown reports whether the state is one of the ServerOSState constants
+func (s ServerOSState) IsKnown() bool {
+	switch s {
+	case ServerOSInstalled, ServerOSInstalling, ServerOSReinstalling,
+		ServerOSFailed:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether the operating system is installed and usable
+func (s ServerOSState) IsActive() bool {
+	return s == ServerOSInstalled
+}
+
+// IsTerminal reports whether the installation is done, successfully or not
+func (s ServerOSState) IsTerminal() bool {
+	return s == ServerOSInstalled || s == ServerOSFailed
+}
+
+// UnmarshalJSON accepts any JSON value, keeping unknown states as they are
+func (s *ServerOSState) UnmarshalJSON(data []byte) error {
+	*s = ServerOSState(unmarshalStatus(data))
+	return nil
+}
+
+// unmarshalStatus returns a JSON string as is, `null` as an empty string,
+// and any other JSON value as its raw text
+func unmarshalStatus(data []byte) string {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err == nil {
+		return value
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,52 @@
+package gonjalla
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusHelpers(t *testing.T) {
+	assert.True(t, DomainActive.IsKnown())
+	assert.True(t, DomainActive.IsActive())
+	assert.True(t, DomainActive.IsTerminal())
+	assert.False(t, DomainPending.IsTerminal())
+	assert.False(t, DomainStatus("parked").IsKnown())
+	assert.False(t, DomainStatus("parked").IsTerminal())
+
+	assert.True(t, MarketAvailable.IsActive())
+	assert.True(t, MarketTaken.IsTerminal())
+	assert.False(t, MarketInProgress.IsTerminal())
+
+	assert.True(t, ServerRunning.IsActive())
+	assert.True(t, ServerStopped.IsTerminal())
+	assert.False(t, ServerStopped.IsActive())
+	assert.False(t, ServerRestarting.IsTerminal())
+	assert.False(t, ServerStatus("").IsKnown())
+
+	assert.True(t, ServerOSInstalled.IsActive())
+	assert.True(t, ServerOSFailed.IsTerminal())
+	assert.False(t, ServerOSInstalling.IsTerminal())
+}
+
+func TestStatusUnmarshalTolerant(t *testing.T) {
+	var server Server
+	err := json.Unmarshal(
+		[]byte(`{"status": "hibernating", "os_state": null}`), &server,
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, ServerStatus("hibernating"), server.Status)
+	assert.False(t, server.Status.IsKnown())
+	assert.Equal(t, ServerOSState(""), server.OsState)
+
+	var domain Domain
+	err = json.Unmarshal([]byte(`{"status": 3}`), &domain)
+	assert.Nil(t, err)
+	assert.Equal(t, DomainStatus("3"), domain.Status)
+
+	var market MarketDomain
+	err = json.Unmarshal([]byte(`{"status": "in progress"}`), &market)
+	assert.Nil(t, err)
+	assert.Equal(t, MarketInProgress, market.Status)
+}
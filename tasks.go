@@ -0,0 +1,242 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TaskState is the state of an asynchronous task
+type TaskState string
+
+// Task states returned by `check-task`
+const (
+	TaskPending    TaskState = "pending"
+	TaskProcessing TaskState = "processing"
+	TaskActive     TaskState = "active"
+	TaskFailed     TaskState = "failed"
+	TaskCancelled  TaskState = "cancelled"
+)
+
+// ErrUnknownTaskState is returned when waiting on a task whose state isn't
+// one of the TaskState constants, instead of polling it forever
+var ErrUnknownTaskState = errors.New("unknown task state")
+
+// IsKnown reports whether the state is one of the TaskState constants
+func (s TaskState) IsKnown() bool {
+	switch s {
+	case TaskPending, TaskProcessing, TaskActive, TaskFailed, TaskCancelled:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the task is done, successfully or not
+func (s TaskState) IsTerminal() bool {
+	return s == TaskActive || s == TaskFailed || s == TaskCancelled
+}
+
+// IsFailure reports whether the task is done and failed
+func (s TaskState) IsFailure() bool {
+	return s == TaskFailed || s == TaskCancelled
+}
+
+// TaskStatus contains data returned by `check-task`
+type TaskStatus struct {
+	ID      string    `json:"id"`
+	Status  TaskState `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// Err returns a *TaskError if the task failed, and nil otherwise
+func (s TaskStatus) Err() error {
+	if !s.Status.IsFailure() {
+		return nil
+	}
+
+	return &TaskError{ID: s.ID, Status: s.Status, Message: s.Error}
+}
+
+// TaskError is returned when waiting on a task that failed
+type TaskError struct {
+	ID      string
+	Status  TaskState
+	Message string
+}
+
+func (e *TaskError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("task %s %s", e.ID, e.Status)
+	}
+	return fmt.Sprintf("task %s %s: %s", e.ID, e.Status, e.Message)
+}
+
+// Task is a handle to an asynchronous operation, like a domain
+// registration. It can be marshalled to JSON and persisted, and later
+// re-attached to a Client with Client.ResumeTask.
+type Task struct {
+	// ID is the task id given by the API
+	ID string `json:"id"`
+	// Method is the API method that started the task, if known
+	Method string `json:"method,omitempty"`
+	// CreatedAt is when the task was started, if known
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	client *Client
+}
+
+// WaitOptions controls how Task.Wait polls the status of a task.
+// The zero value polls every 5 seconds, forever or until the context is
+// done.
+type WaitOptions struct {
+	// Interval is the delay before the first poll. Defaults to 5 seconds.
+	Interval time.Duration
+	// Multiplier is applied to the interval after each poll, to back off on
+	// long running tasks. Defaults to 1, meaning a constant interval.
+	Multiplier float64
+	// MaxInterval caps the interval between two polls
+	MaxInterval time.Duration
+	// Timeout stops waiting after the given duration, if set
+	Timeout time.Duration
+}
+
+// Default interval between two polls of a task
+const defaultTaskInterval = 5 * time.Second
+
+// How long the package-level functions wait on the tasks they start
+const blockingTaskTimeout = time.Hour
+
+func (c *Client) newTask(id string, method string) *Task {
+	createdAt := time.Now().UTC()
+	return &Task{
+		ID:        id,
+		Method:    method,
+		CreatedAt: &createdAt,
+		client:    c,
+	}
+}
+
+// decodeTask returns a Task out of the `{"task": id}` result returned by
+// methods that start asynchronous tasks
+func (c *Client) decodeTask(data []byte, method string) (*Task, error) {
+	type Response struct {
+		Task string `json:"task"`
+	}
+
+	var response Response
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Task == "" {
+		return nil, fmt.Errorf("%s returned no task id: %s", method, data)
+	}
+
+	return c.newTask(response.Task, method), nil
+}
+
+// ResumeTask re-attaches a previously started task to the Client, so it can
+// be waited on, for example after a restart. The task is usually one
+// unmarshalled from JSON, but only its ID is required:
+//
+//	task := client.ResumeTask(gonjalla.Task{ID: id})
+func (c *Client) ResumeTask(task Task) *Task {
+	task.client = c
+	return &task
+}
+
+// Status returns the current status of the task
+func (t *Task) Status(ctx context.Context) (TaskStatus, error) {
+	if t.client == nil {
+		return TaskStatus{}, errors.New(
+			"task has no client, use Client.ResumeTask",
+		)
+	}
+	if t.ID == "" {
+		return TaskStatus{}, errors.New("task has no id")
+	}
+
+	return t.client.CheckTask(ctx, t.ID)
+}
+
+// Wait polls the status of the task until it's done, or until ctx is done
+// or the timeout set in opts passes. Returns the last status seen, and a
+// *TaskError if the task failed. A state this library doesn't know stops
+// the wait too, with ErrUnknownTaskState.
+func (t *Task) Wait(ctx context.Context, opts WaitOptions) (TaskStatus, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultTaskInterval
+	}
+	multiplier := opts.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	for {
+		status, err := t.Status(ctx)
+		if err != nil {
+			return status, err
+		}
+
+		if status.Status.IsTerminal() {
+			return status, status.Err()
+		}
+		if !status.Status.IsKnown() {
+			return status, fmt.Errorf(
+				"task %s: %w %q", t.ID, ErrUnknownTaskState, status.Status,
+			)
+		}
+
+		err = sleepContext(ctx, interval)
+		if err != nil {
+			return status, err
+		}
+
+		interval = time.Duration(float64(interval) * multiplier)
+		if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// CheckTask returns the status of a task given its id
+func (c *Client) CheckTask(ctx context.Context, id string) (TaskStatus, error) {
+	params := map[string]interface{}{
+		"id": id,
+	}
+
+	data, err := c.Request(ctx, "check-task", params)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	var status TaskStatus
+	err = json.Unmarshal(data, &status)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	return status, nil
+}
+
+// CheckTask is a wrapper around Client.CheckTask for the given token.
+// Returns just the state of the task, like `active`.
+func CheckTask(token string, id string) (string, error) {
+	status, err := newDefaultClient(token).CheckTask(context.Background(), id)
+	if err != nil {
+		return "", err
+	}
+
+	return string(status.Status), nil
+}
@@ -0,0 +1,183 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestRegisterDomainTask(t *testing.T) {
+	client, _ := newResponsesClient(
+		t, `{"jsonrpc": "2.0", "result": {"task": "task-1337"}}`,
+	)
+
+	task, err := client.RegisterDomain(context.Background(), "testing.com", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "task-1337", task.ID)
+	assert.Equal(t, "register-domain", task.Method)
+	assert.WithinDuration(t, time.Now(), *task.CreatedAt, time.Minute)
+}
+
+func TestRegisterDomainMissingTask(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"jsonrpc": "2.0", "result": {}}`)
+
+	task, err := client.RegisterDomain(context.Background(), "testing.com", 1)
+	assert.Nil(t, task)
+	assert.Error(t, err)
+
+	_, err = client.ResumeTask(Task{}).Status(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"register-domain"}, methodsOf(*sent))
+}
+
+func TestTaskPersistence(t *testing.T) {
+	client, _ := newResponsesClient(t, `{"result": {}}`)
+
+	created := time.Date(2021, 2, 20, 19, 38, 48, 0, time.UTC)
+	task := &Task{ID: "task-1337", Method: "register-domain", CreatedAt: &created}
+
+	data, err := json.Marshal(task)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id": "task-1337",
+		"method": "register-domain",
+		"created_at": "2021-02-20T19:38:48Z"
+	}`, string(data))
+
+	var restored Task
+	assert.Nil(t, json.Unmarshal(data, &restored))
+	_, err = restored.Status(context.Background())
+	assert.Error(t, err)
+
+	resumed := client.ResumeTask(restored)
+	assert.Equal(t, *task, Task{
+		ID:        resumed.ID,
+		Method:    resumed.Method,
+		CreatedAt: resumed.CreatedAt,
+	})
+	_, err = resumed.Status(context.Background())
+	assert.Nil(t, err)
+
+	data, err = json.Marshal(client.ResumeTask(Task{ID: "task-1337"}))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"id": "task-1337"}`, string(data))
+}
+
+func TestTaskWaitTimeout(t *testing.T) {
+	client, sent := newResponsesClient(
+		t, `{"jsonrpc": "2.0", "result": {"id": "task-1337", "status": "pending"}}`,
+	)
+
+	task := client.ResumeTask(Task{ID: "task-1337"})
+	_, err := task.Wait(context.Background(), WaitOptions{
+		Interval: time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Greater(t, len(*sent), 1)
+	assert.Equal(t, "check-task", (*sent)[0].Method)
+}
+
+func TestCheckTaskExpected(t *testing.T) {
+	client, _ := newResponsesClient(t, `{
+		"jsonrpc": "2.0",
+		"result": {
+			"id": "task-1337",
+			"status": "pending",
+			"created": "2021-02-20T19:38:48Z",
+			"updated": "2021-02-20T19:40:00Z"
+		}
+	}`)
+
+	status, err := client.CheckTask(context.Background(), "task-1337")
+	assert.Nil(t, err)
+
+	expected := TaskStatus{
+		ID:      "task-1337",
+		Status:  TaskPending,
+		Created: time.Date(2021, 2, 20, 19, 38, 48, 0, time.UTC),
+		Updated: time.Date(2021, 2, 20, 19, 40, 0, 0, time.UTC),
+	}
+	assert.Equal(t, expected, status)
+	assert.Nil(t, status.Err())
+}
+
+func TestCheckTaskWrapper(t *testing.T) {
+	DefaultHTTPClient = &mocks.MockClient{}
+	testData := `{"jsonrpc": "2.0", "result": {"id": "1", "status": "active"}}`
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(testData))),
+		}, nil
+	}
+
+	status, err := CheckTask("test-token", "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "active", status)
+}
+
+func TestTaskWaitDone(t *testing.T) {
+	client, sent := newResponsesClient(
+		t,
+		`{"result": {"id": "task-1337", "status": "pending"}}`,
+		`{"result": {"id": "task-1337", "status": "processing"}}`,
+		`{"result": {"id": "task-1337", "status": "active"}}`,
+	)
+
+	task := client.ResumeTask(Task{ID: "task-1337"})
+	status, err := task.Wait(
+		context.Background(), WaitOptions{Interval: time.Millisecond},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, TaskActive, status.Status)
+	assert.Len(t, *sent, 3)
+}
+
+func TestTaskWaitFailed(t *testing.T) {
+	client, sent := newResponsesClient(
+		t,
+		`{"result": {"id": "task-1337", "status": "pending"}}`,
+		`{"result": {"id": "task-1337", "status": "failed", "error": "Taken"}}`,
+	)
+
+	task := client.ResumeTask(Task{ID: "task-1337"})
+	status, err := task.Wait(
+		context.Background(), WaitOptions{Interval: time.Millisecond},
+	)
+
+	var taskErr *TaskError
+	assert.ErrorAs(t, err, &taskErr)
+	assert.Equal(t, "task-1337", taskErr.ID)
+	assert.Equal(t, TaskFailed, taskErr.Status)
+	assert.Equal(t, "Taken", taskErr.Message)
+	assert.Equal(t, TaskFailed, status.Status)
+	assert.Len(t, *sent, 2)
+}
+
+func TestTaskWaitUnknownState(t *testing.T) {
+	client, sent := newResponsesClient(
+		t,
+		`{"result": {"id": "task-1337", "status": "pending"}}`,
+		`{"result": {"id": "task-1337", "status": "done"}}`,
+	)
+
+	task := client.ResumeTask(Task{ID: "task-1337"})
+	status, err := task.Wait(
+		context.Background(), WaitOptions{Interval: time.Millisecond},
+	)
+
+	assert.ErrorIs(t, err, ErrUnknownTaskState)
+	assert.Equal(t, TaskState("done"), status.Status)
+	assert.False(t, status.Status.IsKnown())
+	assert.True(t, TaskProcessing.IsKnown())
+	assert.Len(t, *sent, 2)
+}
@@ -0,0 +1,155 @@
+package gonjalla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// TLD contains data returned by `list-tlds` and `get-tld`
+type TLD struct {
+	// Name is the TLD without a leading dot, like `com` or `co.uk`
+	Name              string `json:"tld"`
+	RegistrationPrice int    `json:"registration_price"`
+	RenewalPrice      int    `json:"renewal_price"`
+	TransferPrice     int    `json:"transfer_price"`
+	Currency          string `json:"currency"`
+	// MinYears and MaxYears bound the term a domain can be registered for
+	MinYears int `json:"min_years"`
+	MaxYears int `json:"max_years"`
+	// DNSSEC tells whether DS records can be added to domains of this TLD
+	DNSSEC bool `json:"dnssec"`
+	// IDN tells whether internationalised domain names are supported
+	IDN bool `json:"idn"`
+	// Restrictions are human readable registration rules of the registry,
+	// like local presence requirements
+	Restrictions []string `json:"restrictions,omitempty"`
+}
+
+// ValidateYears checks that a domain of this TLD can be registered or
+// renewed for the given number of years
+func (t TLD) ValidateYears(years int) error {
+	minYears := t.MinYears
+	if minYears < 1 {
+		minYears = 1
+	}
+	maxYears := t.MaxYears
+	if maxYears < 1 {
+		maxYears = maxDomainYears
+	}
+
+	if years < minYears || years > maxYears {
+		return fmt.Errorf(
+			"years for .%s must be between %d and %d, got %d",
+			t.Name, minYears, maxYears, years,
+		)
+	}
+
+	return nil
+}
+
+// ValidateRegistration checks a RegisterDomain request against the rules of
+// this TLD, before sending it: that the domain belongs to the TLD, that the
+// term is allowed, and that the TLD supports IDNs if the domain is one.
+// Restrictions can't be checked, and are left to the caller.
+func (t TLD) ValidateRegistration(domain string, years int) error {
+	name := strings.ToLower(strings.TrimSuffix(domain, "."))
+	label := strings.TrimSuffix(name, "."+strings.ToLower(t.Name))
+	if label == name || label == "" || strings.Contains(label, ".") {
+		return fmt.Errorf("%s is not a domain of .%s", domain, t.Name)
+	}
+
+	isIDN := strings.HasPrefix(label, "xn--") ||
+		utf8.RuneCountInString(label) != len(label)
+	if isIDN && !t.IDN {
+		return fmt.Errorf(".%s doesn't support IDNs like %s", t.Name, domain)
+	}
+
+	return t.ValidateYears(years)
+}
+
+// tldOf returns the TLD of a registrable domain: everything after its first
+// label, like `co.uk` for `example.co.uk`
+func tldOf(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	i := strings.IndexByte(domain, '.')
+	if i <= 0 || i == len(domain)-1 {
+		return "", fmt.Errorf("%q is not a registrable domain", domain)
+	}
+
+	return domain[i+1:], nil
+}
+
+// ListTLDs returns a listing of all TLDs available at Njalla, with their
+// prices and rules
+func (c *Client) ListTLDs(ctx context.Context) ([]TLD, error) {
+	params := map[string]interface{}{}
+
+	data, err := c.Request(ctx, "list-tlds", params)
+	if err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		TLDs []TLD `json:"tlds"`
+	}
+
+	var response Response
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.TLDs, nil
+}
+
+// ListTLDs is a wrapper around Client.ListTLDs for the given token.
+func ListTLDs(token string) ([]TLD, error) {
+	return newDefaultClient(token).ListTLDs(context.Background())
+}
+
+// GetTLD returns the prices and rules of a single TLD, given without a
+// leading dot
+func (c *Client) GetTLD(ctx context.Context, tld string) (TLD, error) {
+	params := map[string]interface{}{
+		"tld": strings.TrimPrefix(tld, "."),
+	}
+
+	data, err := c.Request(ctx, "get-tld", params)
+	if err != nil {
+		return TLD{}, err
+	}
+
+	var tldStruct TLD
+	err = json.Unmarshal(data, &tldStruct)
+	if err != nil {
+		return TLD{}, err
+	}
+
+	return tldStruct, nil
+}
+
+// GetTLD is a wrapper around Client.GetTLD for the given token.
+func GetTLD(token string, tld string) (TLD, error) {
+	return newDefaultClient(token).GetTLD(context.Background(), tld)
+}
+
+// ValidateRegistration fetches the TLD of a domain, and checks a
+// RegisterDomain request against its rules with TLD.ValidateRegistration
+func (c *Client) ValidateRegistration(
+	ctx context.Context, domain string, years int,
+) error {
+	name, err := tldOf(domain)
+	if err != nil {
+		return err
+	}
+
+	tld, err := c.GetTLD(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return tld.ValidateRegistration(domain, years)
+}
@@ -0,0 +1,146 @@
+package gonjalla
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sighery/gonjalla/mocks"
+)
+
+func TestListTLDsExpected(t *testing.T) {
+	token := "test-token"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"result": {
+			"tlds": [
+				{
+					"tld": "com",
+					"registration_price": 15,
+					"renewal_price": 15,
+					"transfer_price": 15,
+					"currency": "EUR",
+					"min_years": 1,
+					"max_years": 10,
+					"dnssec": true,
+					"idn": true
+				},
+				{
+					"tld": "eu",
+					"registration_price": 30,
+					"renewal_price": 30,
+					"transfer_price": 0,
+					"currency": "EUR",
+					"min_years": 1,
+					"max_years": 1,
+					"dnssec": true,
+					"idn": false,
+					"restrictions": ["Registrant must reside in the EU"]
+				}
+			]
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	tlds, err := ListTLDs(token)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []TLD{
+		{
+			Name:              "com",
+			RegistrationPrice: 15,
+			RenewalPrice:      15,
+			TransferPrice:     15,
+			Currency:          "EUR",
+			MinYears:          1,
+			MaxYears:          10,
+			DNSSEC:            true,
+			IDN:               true,
+		},
+		{
+			Name:              "eu",
+			RegistrationPrice: 30,
+			RenewalPrice:      30,
+			TransferPrice:     0,
+			Currency:          "EUR",
+			MinYears:          1,
+			MaxYears:          1,
+			DNSSEC:            true,
+			IDN:               false,
+			Restrictions:      []string{"Registrant must reside in the EU"},
+		},
+	}
+
+	assert.Equal(t, tlds, expected)
+}
+
+func TestGetTLDError(t *testing.T) {
+	token := "test-token"
+	DefaultHTTPClient = &mocks.MockClient{}
+
+	testData := `{
+		"jsonrpc": "2.0",
+		"error": {
+			"code": 0,
+			"message": "Testing error"
+		}
+	}`
+	r := ioutil.NopCloser(bytes.NewReader([]byte(testData)))
+
+	mocks.GetDoFunc = func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       r,
+		}, nil
+	}
+
+	_, err := GetTLD(token, "com")
+	assert.Error(t, err)
+}
+
+func TestTLDValidateRegistration(t *testing.T) {
+	tld := TLD{Name: "co.uk", MinYears: 1, MaxYears: 2, IDN: false}
+
+	assert.Nil(t, tld.ValidateRegistration("example.co.uk", 1))
+	assert.Nil(t, tld.ValidateRegistration("Example.co.uk.", 2))
+
+	assert.Error(t, tld.ValidateRegistration("example.co.uk", 0))
+	assert.Error(t, tld.ValidateRegistration("example.co.uk", 3))
+	assert.Error(t, tld.ValidateRegistration("example.uk", 1))
+	assert.Error(t, tld.ValidateRegistration("www.example.co.uk", 1))
+	assert.Error(t, tld.ValidateRegistration("co.uk", 1))
+	assert.Error(t, tld.ValidateRegistration("xn--bcher-kva.co.uk", 1))
+	assert.Error(t, tld.ValidateRegistration("bücher.co.uk", 1))
+
+	tld.IDN = true
+	assert.Nil(t, tld.ValidateRegistration("bücher.co.uk", 1))
+}
+
+func TestClientValidateRegistration(t *testing.T) {
+	client, sent := newResponsesClient(t, `{
+		"jsonrpc": "2.0",
+		"result": {"tld": "com", "min_years": 1, "max_years": 10}
+	}`)
+
+	err := client.ValidateRegistration(context.Background(), "testing.com", 11)
+	assert.Error(t, err)
+	assert.Equal(t, []request{{
+		Method: "get-tld",
+		Params: map[string]interface{}{"tld": "com"},
+	}}, *sent)
+}
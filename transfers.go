@@ -0,0 +1,98 @@
+package gonjalla
+
+import (
+	"context"
+	"errors"
+)
+
+// TransferPhase is the phase an inbound domain transfer is in
+type TransferPhase string
+
+// Phases of an inbound domain transfer
+const (
+	// TransferPending means the transfer was requested, and is waiting for
+	// the current registrar
+	TransferPending TransferPhase = "pending"
+	// TransferApproved means the transfer was approved, and is being carried
+	// out
+	TransferApproved TransferPhase = "approved"
+	// TransferCompleted means the domain is now registered with Njalla
+	TransferCompleted TransferPhase = "completed"
+	// TransferRejected means the transfer was rejected or cancelled
+	TransferRejected TransferPhase = "rejected"
+)
+
+// TransferPhaseOf maps the state of an `import-domain` task to the phase of
+// the transfer. Unknown states are taken as pending.
+func TransferPhaseOf(state TaskState) TransferPhase {
+	switch state {
+	case TaskProcessing:
+		return TransferApproved
+	case TaskActive:
+		return TransferCompleted
+	case TaskFailed, TaskCancelled:
+		return TransferRejected
+	default:
+		return TransferPending
+	}
+}
+
+// Transfer tracks an inbound domain transfer started with ImportDomain.
+// It's a Task, so it can be waited on, persisted and resumed the same way.
+type Transfer struct {
+	*Task
+}
+
+// Phase returns the current phase of the transfer
+func (t *Transfer) Phase(ctx context.Context) (TransferPhase, error) {
+	status, err := t.Status(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return TransferPhaseOf(status.Status), nil
+}
+
+// ImportDomain starts transferring a domain from another registrar to
+// Njalla, given its auth code. Transfers can take days; the returned
+// Transfer can be persisted and resumed later with Client.ResumeTransfer.
+func (c *Client) ImportDomain(
+	ctx context.Context, domain string, authCode AuthCode,
+) (*Transfer, error) {
+	if authCode.IsZero() {
+		return nil, errors.New("auth code must not be empty")
+	}
+
+	params := map[string]interface{}{
+		"domain":    domain,
+		"auth_code": authCode.Reveal(),
+	}
+
+	data, err := c.Request(ctx, "import-domain", params)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := c.decodeTask(data, "import-domain")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transfer{Task: task}, nil
+}
+
+// ImportDomain is a wrapper around Client.ImportDomain for the given token.
+func ImportDomain(
+	token string, domain string, authCode AuthCode,
+) (*Transfer, error) {
+	return newDefaultClient(token).ImportDomain(
+		context.Background(), domain, authCode,
+	)
+}
+
+// ResumeTransfer re-attaches a previously started transfer to the Client, so
+// its phase can be tracked, for example after a restart. Like
+// Client.ResumeTask, only the ID of the task is required.
+func (c *Client) ResumeTransfer(task Task) *Transfer {
+	return &Transfer{Task: c.ResumeTask(task)}
+}
@@ -0,0 +1,77 @@
+package gonjalla
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportDomainExpected(t *testing.T) {
+	client, sent := newResponsesClient(
+		t,
+		`{"jsonrpc": "2.0", "result": {"task": "task-1337"}}`,
+		`{"jsonrpc": "2.0", "result": {"id": "task-1337", "status": "pending"}}`,
+		`{"jsonrpc": "2.0", "result": {"id": "task-1337", "status": "processing"}}`,
+		`{"jsonrpc": "2.0", "result": {"id": "task-1337", "status": "active"}}`,
+	)
+	ctx := context.Background()
+
+	transfer, err := client.ImportDomain(
+		ctx, "testing.com", NewAuthCode("s3cr3t-c0de"),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "task-1337", transfer.ID)
+	assert.Equal(t, "import-domain", transfer.Method)
+
+	phase, err := transfer.Phase(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferPending, phase)
+
+	phase, err = transfer.Phase(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferApproved, phase)
+
+	status, err := transfer.Wait(ctx, WaitOptions{Interval: time.Millisecond})
+	assert.Nil(t, err)
+	assert.Equal(t, TransferCompleted, TransferPhaseOf(status.Status))
+
+	assert.Equal(t, []string{
+		"import-domain", "check-task", "check-task", "check-task",
+	}, methodsOf(*sent))
+}
+
+func TestImportDomainEmptyAuthCode(t *testing.T) {
+	client, sent := newResponsesClient(t, `{"result": {}}`)
+
+	_, err := client.ImportDomain(
+		context.Background(), "testing.com", AuthCode{},
+	)
+	assert.Error(t, err)
+	assert.Empty(t, *sent)
+}
+
+func TestResumeTransferRejected(t *testing.T) {
+	client, _ := newResponsesClient(
+		t, `{"result": {"id": "task-1337", "status": "failed"}}`,
+	)
+
+	transfer := client.ResumeTransfer(Task{ID: "task-1337"})
+	phase, err := transfer.Phase(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, TransferRejected, phase)
+
+	_, err = transfer.Wait(context.Background(), WaitOptions{})
+	var taskErr *TaskError
+	assert.ErrorAs(t, err, &taskErr)
+}
+
+func TestTransferPhaseOf(t *testing.T) {
+	assert.Equal(t, TransferPending, TransferPhaseOf(TaskPending))
+	assert.Equal(t, TransferApproved, TransferPhaseOf(TaskProcessing))
+	assert.Equal(t, TransferCompleted, TransferPhaseOf(TaskActive))
+	assert.Equal(t, TransferRejected, TransferPhaseOf(TaskFailed))
+	assert.Equal(t, TransferRejected, TransferPhaseOf(TaskCancelled))
+	assert.Equal(t, TransferPending, TransferPhaseOf("unknown"))
+}